@@ -0,0 +1,163 @@
+package hrtime
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// NewTickerFromFD creates a MonotonicTicker driven by fd, an existing timerfd
+// (received from another process by fd passing, for instance), rather than
+// by a timerfd of its own.  The ticker takes the clock of fd, and arms it at
+// the interval on Start(), as it would its own timer; fd is made
+// non-blocking.  The options are those of NewMonotonicTicker, except that
+// WithClock and backend options do not apply.  It returns an error if fd is
+// not a timerfd, in which case fd is left alone.
+//
+// By default, the ticker owns fd: the first Stop() closes it, after which the
+// ticker cannot be started again.  With WithRetainedFD, the caller keeps
+// ownership: Stop() disarms fd but leaves it open, the ticker can be
+// restarted, and the caller must close fd once the ticker is stopped for
+// good.  A retained ticker holds a duplicate of fd, so it keeps driving the
+// same timer even if the caller closes fd early and the fd number is reused.
+func NewTickerFromFD(fd int, interval time.Duration, opts ...Option) (*MonotonicTicker, error) {
+	clock, err := timerfdClock(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &adoptedFDBackend{clock: clock}
+	ticker := NewMonotonicTicker(interval, withOptions(opts, WithClock(clock), WithBackend(backend))...)
+	backend.retain = ticker.config.retainFD
+
+	// A retained fd stays the caller's, so the backend keeps a duplicate
+	// of its own, which the caller closing (and the kernel reusing) fd
+	// cannot affect.
+	if backend.retain {
+		dup, err := unix.FcntlInt(uintptr(fd), unix.F_DUPFD_CLOEXEC, 0)
+		if err != nil {
+			return nil, err
+		}
+		fd = dup
+	}
+	backend.file = os.NewFile(uintptr(fd), "timerfd")
+
+	return ticker, nil
+}
+
+// WithRetainedFD makes a ticker created by NewTickerFromFD leave its fd open
+// when it stops, so that the caller keeps ownership of it.
+func WithRetainedFD() Option {
+	return func(config *tickerConfig) {
+		config.retainFD = true
+	}
+}
+
+// ErrAdoptedFDClosed is returned by Start() when a ticker created by
+// NewTickerFromFD, without WithRetainedFD, has already closed its fd.
+var ErrAdoptedFDClosed = errors.New("the ticker's adopted timerfd was closed when it stopped")
+
+// timerfdClock returns the clock of the timerfd fd, from its entry in
+// /proc/self/fdinfo, or an error if fd is not a timerfd.
+func timerfdClock(fd int) (ClockID, error) {
+	target, err := os.Readlink(fmt.Sprintf("/proc/self/fd/%d", fd))
+	if err != nil {
+		return 0, err
+	}
+	if target != "anon_inode:[timerfd]" {
+		return 0, fmt.Errorf("fd %d is not a timerfd (it is %s)", fd, target)
+	}
+
+	info, err := os.Open(fmt.Sprintf("/proc/self/fdinfo/%d", fd))
+	if err != nil {
+		return 0, err
+	}
+	defer info.Close()
+
+	scanner := bufio.NewScanner(info)
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), "clockid:"); found {
+			clock, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return 0, fmt.Errorf("on parsing the clock of fd %d: %w", fd, err)
+			}
+			return ClockID(clock), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("no clock is reported for fd %d", fd)
+}
+
+// adoptedFDBackend is the Backend of a ticker created by NewTickerFromFD.
+// file wraps the adopted fd (or, if the caller retains it, a duplicate).  Each
+// timer it creates wraps a duplicate of file, so that closing the timer
+// (which is how a ticker stops its read goroutine) does not close file itself
+// unless the ticker owns it.  Like timerfdTimer, it only uses the fd through
+// file.
+type adoptedFDBackend struct {
+	file   *os.File
+	clock  ClockID
+	retain bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (backend *adoptedFDBackend) NewTimer(clock ClockID) (BackendTimer, error) {
+	if clock != backend.clock {
+		return nil, fmt.Errorf("%w (%s): the adopted timerfd is on %s", ErrUnsupportedClock, clock, backend.clock)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	if backend.closed {
+		return nil, ErrAdoptedFDClosed
+	}
+
+	raw, err := backend.file.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	dup, dupErr := -1, error(nil)
+	if err := raw.Control(func(fd uintptr) { dup, dupErr = unix.FcntlInt(fd, unix.F_DUPFD_CLOEXEC, 0) }); err != nil {
+		return nil, err
+	}
+	if dupErr != nil {
+		return nil, dupErr
+	}
+	if err := unix.SetNonblock(dup, true); err != nil {
+		unix.Close(dup)
+		return nil, err
+	}
+
+	return &timerfdTimer{file: os.NewFile(uintptr(dup), "timerfd"), clock: clock, b: make([]byte, 8), afterClose: backend.release}, nil
+}
+
+// release is called after each of the backend's timers is closed.  It
+// disarms the timer if the caller retains fd, and otherwise closes it.
+func (backend *adoptedFDBackend) release() {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	if backend.retain {
+		settimeUsingFile(backend.file, 0, &unix.ItimerSpec{})
+		return
+	}
+
+	if !backend.closed {
+		backend.file.Close()
+		backend.closed = true
+	}
+}
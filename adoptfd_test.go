@@ -0,0 +1,84 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"golang.org/x/sys/unix"
+)
+
+func TestNewTickerFromFD(t *testing.T) {
+	fd, err := unix.TimerfdCreate(unix.CLOCK_BOOTTIME, unix.TFD_CLOEXEC)
+	if err != nil {
+		t.Fatalf("on timerfd_create(): %s", err.Error())
+	}
+
+	ticker, err := hrtime.NewTickerFromFD(fd, 2*time.Millisecond)
+	if err != nil {
+		t.Fatalf("on NewTickerFromFD(): %s", err.Error())
+	}
+	if clock := *ticker.Config().Clock; clock != hrtime.ClockBoottime {
+		t.Errorf("expected the ticker to take the clock of the fd, got %s", clock)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	if _, isOpen := <-ticker.C; !isOpen {
+		t.Fatalf("expected a tick from the adopted fd")
+	}
+	ticker.Stop()
+
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0); !errors.Is(err, unix.EBADF) {
+		t.Errorf("expected the owned fd to be closed by Stop(), got %v", err)
+	}
+	if err := ticker.Start(); !errors.Is(err, hrtime.ErrAdoptedFDClosed) {
+		t.Errorf("expected Start() after the fd was closed to return ErrAdoptedFDClosed, got %v", err)
+	}
+}
+
+func TestNewTickerFromFDRetained(t *testing.T) {
+	fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, unix.TFD_CLOEXEC)
+	if err != nil {
+		t.Fatalf("on timerfd_create(): %s", err.Error())
+	}
+	defer unix.Close(fd)
+
+	ticker, err := hrtime.NewTickerFromFD(fd, 2*time.Millisecond, hrtime.WithRetainedFD())
+	if err != nil {
+		t.Fatalf("on NewTickerFromFD(): %s", err.Error())
+	}
+
+	for run := 0; run < 2; run++ {
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() of run %d: %s", run, err.Error())
+		}
+		if _, isOpen := <-ticker.C; !isOpen {
+			t.Fatalf("expected a tick from the adopted fd in run %d", run)
+		}
+		ticker.Stop()
+
+		var spec unix.ItimerSpec
+		if err := unix.TimerfdGettime(fd, &spec); err != nil {
+			t.Fatalf("expected the retained fd to stay open, got %s", err.Error())
+		}
+		if spec.Value.Nano() != 0 {
+			t.Errorf("expected Stop() to disarm the retained fd")
+		}
+	}
+}
+
+func TestNewTickerFromFDRejectsOtherFiles(t *testing.T) {
+	var pipe [2]int
+	if err := unix.Pipe2(pipe[:], unix.O_CLOEXEC); err != nil {
+		t.Fatalf("on pipe2(): %s", err.Error())
+	}
+	defer unix.Close(pipe[0])
+	defer unix.Close(pipe[1])
+
+	if _, err := hrtime.NewTickerFromFD(pipe[0], time.Millisecond); err == nil {
+		t.Errorf("expected NewTickerFromFD() of a pipe to fail")
+	}
+}
@@ -0,0 +1,240 @@
+package hrtime
+
+import (
+	"errors"
+	"os"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// A Backend creates the timers that drive tickers.  The default backend uses
+// Linux timerfd.  Alternative backends can be provided with WithBackend,
+// which is mostly useful for substituting a fake in tests (see the
+// hrtimetest package).
+type Backend interface {
+	// NewTimer creates a disarmed timer on the provided clock.
+	NewTimer(clock ClockID) (BackendTimer, error)
+}
+
+// A BackendTimer is a single interval timer with the semantics of a timerfd.
+// A ticker calls Read from its read goroutine, and the other methods from
+// whichever goroutine calls the ticker's methods; a BackendTimer must allow
+// Settime, Gettime, Now and Close to be called while a Read is blocked.
+type BackendTimer interface {
+	// Settime arms the timer, as timerfd_settime(2) does.  flags is 0 or
+	// unix.TFD_TIMER_ABSTIME.  A zero spec.Value disarms the timer.
+	Settime(flags int, spec *unix.ItimerSpec) error
+
+	// Gettime returns the time until the next expiration and the interval,
+	// as timerfd_gettime(2) does.
+	Gettime() (*unix.ItimerSpec, error)
+
+	// Read blocks until the timer has expired at least once since the
+	// previous Read, then returns the number of expirations.  After Close
+	// is called, a blocked or subsequent Read returns an error.  An error
+	// wrapping unix.EAGAIN or unix.EINTR means that nothing was read, and
+	// the ticker calls Read again at once; any other error stops the
+	// ticker.
+	Read() (uint64, error)
+
+	// Now returns the current time on the timer's clock.
+	Now() (time.Duration, error)
+
+	// Close releases the timer.
+	Close() error
+}
+
+// A PendingReader is a BackendTimer that can also read its expirations
+// without waiting for them.  StopWithResidual() uses it when the ticker's
+// timer provides it.
+type PendingReader interface {
+	// ReadPending consumes and returns the expirations since the previous
+	// read, as Read does, except that it returns 0 rather than blocking
+	// when there are none.  It may be called while a Read is blocked.
+	ReadPending() (uint64, error)
+}
+
+// WithBackend sets the backend used to create the ticker's timers.
+func WithBackend(backend Backend) Option {
+	return func(config *tickerConfig) {
+		config.backend = backend
+	}
+}
+
+// timerfdBackend is the default Backend.
+type timerfdBackend struct{}
+
+func (timerfdBackend) NewTimer(clock ClockID) (BackendTimer, error) {
+	timerFile, err := timerfdCreate(clock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &timerfdTimer{file: timerFile, clock: clock, b: make([]byte, 8)}, nil
+}
+
+// timerfdTimer is a BackendTimer for a timerfd.  The fd is only ever used
+// through the os.File, which prevents operations on a closed (and possibly
+// reused) fd number.
+type timerfdTimer struct {
+	file  *os.File
+	clock ClockID
+	b     []byte
+
+	// afterClose, if it is not nil, is called after the file is closed.
+	afterClose func()
+}
+
+var errShortTimerfdRead = errors.New("short read from timerfd")
+
+func (timer *timerfdTimer) Settime(flags int, spec *unix.ItimerSpec) error {
+	return settimeUsingFile(timer.file, flags, spec)
+}
+
+func (timer *timerfdTimer) Gettime() (*unix.ItimerSpec, error) {
+	return gettimeUsingFile(timer.file)
+}
+
+// timerfdPollTimeout bounds each wait of timerfdTimer.Read() for the timerfd
+// to become readable, when it cannot rely on the runtime poller, so that a
+// Close() is noticed.
+const timerfdPollTimeout = 10 * time.Millisecond
+
+// Read reads the timerfd's expiration count.  The timerfd is non-blocking,
+// so os.File registers it with the runtime poller, whose Read parks the
+// goroutine while the timerfd has nothing to read (the kernel returns
+// EAGAIN) and retries once it is readable.  If the poller cannot be used for
+// the file, the EAGAIN reaches Read, which then waits with poll(2) and
+// retries, rather than failing.  A read of fewer than 8 bytes (which the
+// kernel never returns for a timerfd) is an error.
+func (timer *timerfdTimer) Read() (uint64, error) {
+	for {
+		bytesRead, err := timer.file.Read(timer.b)
+		if errors.Is(err, unix.EAGAIN) {
+			if err := timer.waitReadable(); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if bytesRead != 8 {
+			return 0, errShortTimerfdRead
+		}
+
+		break
+	}
+
+	// read bytes are in host byte order
+	return *(*uint64)(unsafe.Pointer(&timer.b[0])), nil
+}
+
+// waitReadable waits, for at most timerfdPollTimeout, until the timerfd is
+// readable.
+func (timer *timerfdTimer) waitReadable() error {
+	raw, err := timer.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var pollErr error
+	if err := raw.Control(func(fd uintptr) {
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		_, pollErr = unix.Poll(fds, int(timerfdPollTimeout/time.Millisecond))
+	}); err != nil {
+		return err
+	}
+	if errors.Is(pollErr, unix.EINTR) {
+		return nil
+	}
+
+	return pollErr
+}
+
+// ReadPending reads the timerfd directly, bypassing the os.File (whose read
+// lock is held by a blocked Read), which is safe as the fd is non-blocking.
+func (timer *timerfdTimer) ReadPending() (uint64, error) {
+	raw, err := timer.file.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var b [8]byte
+	var bytesRead int
+	var readErr error
+	if err := raw.Control(func(fd uintptr) { bytesRead, readErr = unix.Read(int(fd), b[:]) }); err != nil {
+		return 0, err
+	}
+	if errors.Is(readErr, unix.EAGAIN) {
+		return 0, nil
+	}
+	if readErr != nil {
+		return 0, readErr
+	}
+	if bytesRead != 8 {
+		return 0, errShortTimerfdRead
+	}
+
+	return *(*uint64)(unsafe.Pointer(&b[0])), nil
+}
+
+func (timer *timerfdTimer) Now() (time.Duration, error) {
+	return clockNow(int32(timer.clock))
+}
+
+func (timer *timerfdTimer) Close() error {
+	err := timer.file.Close()
+	if timer.afterClose != nil && err == nil {
+		timer.afterClose()
+	}
+
+	return err
+}
+
+// settimeUsingFile arms or disarms the timerfd wrapped by f.  flags may be 0
+// or unix.TFD_TIMER_ABSTIME.
+func settimeUsingFile(f *os.File, flags int, itimerSpec *unix.ItimerSpec) error {
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fdSettimeError error
+	err = raw.Control(func(fdInControl uintptr) {
+		fdSettimeError = unix.TimerfdSettime(int(fdInControl), flags, itimerSpec, nil)
+	})
+
+	if fdSettimeError != nil {
+		return fdSettimeError
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gettimeUsingFile(f *os.File) (*unix.ItimerSpec, error) {
+	raw, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &unix.ItimerSpec{}
+	var fdGettimeError error
+	err = raw.Control(func(fdInControl uintptr) {
+		fdGettimeError = unix.TimerfdGettime(int(fdInControl), spec)
+	})
+
+	if fdGettimeError != nil {
+		return nil, fdGettimeError
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
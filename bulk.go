@@ -0,0 +1,43 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StartAll starts each of the tickers.  It is all-or-nothing: if any ticker
+// fails to start, the tickers that StartAll already started are stopped
+// again, and the returned error reports which ticker failed (by index).
+// Tickers that were already running before StartAll was called are never
+// stopped by it.
+func StartAll[T any](tickers []*Ticker[T]) error {
+	for i, ticker := range tickers {
+		if err := ticker.Start(); err != nil {
+			errs := []error{fmt.Errorf("on Start() of ticker %d: %w", i, err)}
+
+			for j := i - 1; j >= 0; j-- {
+				if err := tickers[j].Stop(); err != nil {
+					errs = append(errs, fmt.Errorf("on Stop() of ticker %d after failed start: %w", j, err))
+				}
+			}
+
+			return errors.Join(errs...)
+		}
+	}
+
+	return nil
+}
+
+// StopAll stops each of the tickers.  Every ticker is stopped even if
+// stopping an earlier one fails; the returned error combines the errors, if
+// any, reporting which tickers failed (by index).
+func StopAll[T any](tickers []*Ticker[T]) error {
+	var errs []error
+	for i, ticker := range tickers {
+		if err := ticker.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("on Stop() of ticker %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestStartAllStopAll(t *testing.T) {
+	tickers := []*hrtime.MonotonicTicker{
+		hrtime.NewMonotonicTicker(5 * time.Millisecond),
+		hrtime.NewMonotonicTicker(7 * time.Millisecond),
+	}
+
+	if err := hrtime.StartAll(tickers); err != nil {
+		t.Fatalf("on StartAll(): %s", err.Error())
+	}
+
+	for i, ticker := range tickers {
+		if _, open := <-ticker.C; !open {
+			t.Errorf("on read of ticker %d, channel is closed", i)
+		}
+	}
+
+	if err := hrtime.StopAll(tickers); err != nil {
+		t.Fatalf("on StopAll(): %s", err.Error())
+	}
+}
+
+func TestStartAllIsAllOrNothing(t *testing.T) {
+	alreadyRunning := hrtime.NewMonotonicTicker(5 * time.Millisecond)
+	if err := alreadyRunning.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer alreadyRunning.Stop()
+
+	first := hrtime.NewMonotonicTicker(5 * time.Millisecond)
+	tickers := []*hrtime.MonotonicTicker{first, alreadyRunning}
+
+	if err := hrtime.StartAll(tickers); err == nil {
+		t.Fatalf("expected error on StartAll() including a running ticker")
+	}
+
+	for range first.C {
+	}
+
+	if _, open := <-alreadyRunning.C; !open {
+		t.Errorf("expected ticker that was already running to be left running")
+	}
+}
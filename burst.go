@@ -0,0 +1,152 @@
+package hrtime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A BurstTick describes one tick of a burst.
+type BurstTick struct {
+	// Burst is the number of the burst, counting from 0 for the first
+	// burst after Start().
+	Burst uint64
+
+	// Index is the position of the tick in its burst, from 0 to one less
+	// than the burst count.
+	Index int
+
+	// FiredAt is the time at which the expiration of the tick was observed.
+	FiredAt MonotonicTime
+}
+
+// A BurstTicker fires in bursts: every period, it fires a burst of ticks, a
+// fixed spacing apart, and then waits for the next period.
+type BurstTicker struct {
+	// After the ticker is started, a BurstTick is written to this channel
+	// for each tick of each burst.  The channel is closed when the ticker
+	// is stopped.
+	C              chan BurstTick
+	period         time.Duration
+	burstCount     int
+	burstSpacing   time.Duration
+	mu             sync.Mutex
+	tickFile       *os.File
+	stop           chan struct{}
+	inStoppedState bool
+}
+
+// NewBurstTicker creates a ticker that fires burstCount times, burstSpacing
+// apart, at the start of every period: with a period of a second, a count of
+// 4 and a spacing of 10ms, it fires at 1s, 1.01s, 1.02s and 1.03s after
+// Start(), then at 2s, 2.01s, and so on.  The period and the count must be
+// positive, the spacing must not be negative, and a burst must fit in a
+// period: burstCount*burstSpacing must be less than period.
+func NewBurstTicker(period time.Duration, burstCount int, burstSpacing time.Duration) (*BurstTicker, error) {
+	if period <= 0 {
+		return nil, fmt.Errorf("period must be positive, got %s", period)
+	}
+	if burstCount <= 0 {
+		return nil, fmt.Errorf("burst count must be positive, got %d", burstCount)
+	}
+	if burstSpacing < 0 {
+		return nil, fmt.Errorf("burst spacing must not be negative, got %s", burstSpacing)
+	}
+	// burstCount*burstSpacing < period, without overflowing.
+	if burstSpacing > 0 && int64(burstCount) > int64((period-1)/burstSpacing) {
+		return nil, fmt.Errorf("a burst of %d ticks %s apart does not fit in a period of %s", burstCount, burstSpacing, period)
+	}
+
+	return &BurstTicker{
+		period:         period,
+		burstCount:     burstCount,
+		burstSpacing:   burstSpacing,
+		inStoppedState: true,
+	}, nil
+}
+
+// Start starts the bursts, the first of them one period from now.  Like a
+// ScheduleTicker, the ticker arms its timer for the absolute time, on
+// CLOCK_MONOTONIC, of each tick in turn, measured from the moment Start() is
+// called, so lateness in delivering one tick does not delay the ones that
+// follow, and the bursts do not drift.  Each tick is delivered with a
+// blocking send, so none is skipped: if the receiver is slow, the ticks whose
+// times have already passed are delivered as soon as it is ready.  Each time
+// Start() is run, ticker.C is replaced with a new channel, and the bursts
+// are counted from 0 again.
+func (ticker *BurstTicker) Start() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if !ticker.inStoppedState {
+		return fmt.Errorf("must Stop() before performing Start() again")
+	}
+
+	timerFile, err := timerfdCreate(ClockMonotonic)
+	if err != nil {
+		return err
+	}
+
+	startedAt, err := clockNow(unix.CLOCK_MONOTONIC)
+	if err != nil {
+		timerFile.Close()
+		return err
+	}
+
+	ticker.C = make(chan BurstTick)
+	ticker.tickFile = timerFile
+	ticker.stop = make(chan struct{})
+	ticker.inStoppedState = false
+
+	go ticker.run(timerFile, ticker.C, ticker.stop, startedAt)
+
+	return nil
+}
+
+func (ticker *BurstTicker) run(timerFile *os.File, c chan BurstTick, stop chan struct{}, startedAt time.Duration) {
+	defer close(c)
+	defer timerFile.Close()
+
+	period, spacing := scaled(ticker.period), scaled(ticker.burstSpacing)
+
+	b := make([]byte, 8)
+	for burst := uint64(0); ; burst++ {
+		burstAt := startedAt + time.Duration(burst+1)*period
+		for i := 0; i < ticker.burstCount; i++ {
+			deadline := &unix.ItimerSpec{Value: unix.NsecToTimespec(int64(burstAt + time.Duration(i)*spacing))}
+			if err := settimeUsingFile(timerFile, unix.TFD_TIMER_ABSTIME, deadline); err != nil {
+				return
+			}
+
+			if bytesRead, err := timerFile.Read(b); bytesRead != 8 || err != nil {
+				return
+			}
+
+			select {
+			case c <- BurstTick{Burst: burst, Index: i, FiredAt: MonotonicNow()}:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// Stop stops the bursts, if they are running.  The associated channel will be
+// closed from the ticker side.
+func (ticker *BurstTicker) Stop() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return nil
+	}
+
+	close(ticker.stop)
+	ticker.tickFile.Close()
+	ticker.inStoppedState = true
+
+	return nil
+}
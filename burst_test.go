@@ -0,0 +1,56 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestBurstTicker(t *testing.T) {
+	for _, invalid := range []struct {
+		period  time.Duration
+		count   int
+		spacing time.Duration
+	}{
+		{0, 1, 0},
+		{time.Second, 0, 0},
+		{time.Second, 2, -time.Millisecond},
+		{time.Second, 4, 250 * time.Millisecond},
+		{time.Second, 1 << 40, time.Hour},
+	} {
+		if _, err := hrtime.NewBurstTicker(invalid.period, invalid.count, invalid.spacing); err == nil {
+			t.Errorf("expected error on NewBurstTicker(%s, %d, %s)", invalid.period, invalid.count, invalid.spacing)
+		}
+	}
+
+	const period, spacing = 50 * time.Millisecond, 5 * time.Millisecond
+	ticker, err := hrtime.NewBurstTicker(period, 3, spacing)
+	if err != nil {
+		t.Fatalf("on NewBurstTicker(): %s", err.Error())
+	}
+
+	startedAt := hrtime.MonotonicNow()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	for burst := uint64(0); burst < 2; burst++ {
+		for index := 0; index < 3; index++ {
+			tick := <-ticker.C
+			if tick.Burst != burst || tick.Index != index {
+				t.Fatalf("expected tick %d of burst %d, got tick %d of burst %d", index, burst, tick.Index, tick.Burst)
+			}
+
+			due := time.Duration(burst+1)*period + time.Duration(index)*spacing
+			if elapsed := tick.FiredAt.Sub(startedAt); elapsed < due || elapsed > due+10*time.Millisecond {
+				t.Errorf("expected tick %d of burst %d at %s, got it at %s", index, burst, due, elapsed)
+			}
+		}
+	}
+
+	ticker.Stop()
+	for range ticker.C {
+	}
+}
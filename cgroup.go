@@ -0,0 +1,220 @@
+package hrtime
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The assumed CPU cost of one tick (the wake-up of the read goroutine, the
+// read of the timer, and a send on the channel), and the share of a CPU
+// quota that ticking may use, by ContainerAwareMaxRate.
+const (
+	estimatedTickCPUCost = 5 * time.Microsecond
+	tickingQuotaShare    = 0.1
+)
+
+// CPUQuota returns the CPU quota of the calling process's cgroup, in CPUs
+// (a quota of 50ms of CPU time per 100ms period is 0.5), and whether there
+// is a quota at all.  The quota is the smallest of those set on the cgroup
+// and its ancestors, as the kernel enforces each of them.
+//
+// The cgroup is taken from /proc/self/cgroup, and its files are looked up
+// under /sys/fs/cgroup.  On cgroup v2, whose /proc/self/cgroup line has a
+// hierarchy ID of 0 and no controllers, the quota is read from cpu.max
+// ("max" when there is none).  On cgroup v1, it is read from
+// cpu.cfs_quota_us (-1 when there is none) and cpu.cfs_period_us, in the
+// hierarchy of the line that lists the cpu controller, mounted at
+// /sys/fs/cgroup/cpu,cpuacct or /sys/fs/cgroup/cpu.  If both are present (a
+// hybrid setup), the cpu controller can only be bound to the v1 hierarchy,
+// so v1 is used.  Inside a container with its own cgroup namespace, the
+// cgroup's path is "/", and its files are at the root of the mount.  If no
+// quota files can be found, CPUQuota reports that there is no quota.
+func CPUQuota() (cpus float64, limited bool, err error) {
+	return readCPUQuota("/proc/self/cgroup", "/sys/fs/cgroup")
+}
+
+// ContainerAwareMaxRate estimates the highest tick rate, in ticks per second,
+// that a ticker can sustain without risking the throttling of the process by
+// its CPU quota (see CPUQuota): the rate at which ticking, at an assumed cost
+// of 5µs of CPU time per tick, would use a tenth of the quota, leaving the
+// rest for the work that the ticks drive.  A throttled process stops running
+// until its next quota period, which shows up as ticks that are late by up
+// to the period (100ms by default), so rates well below this are advisable
+// when the per-tick work is substantial.  It returns +Inf if there is no
+// quota.  WithCPUQuotaWarning checks a ticker's rate against it.
+func ContainerAwareMaxRate() (float64, error) {
+	cpus, limited, err := CPUQuota()
+	if err != nil || !limited {
+		return math.Inf(1), err
+	}
+
+	return cpus * tickingQuotaShare / estimatedTickCPUCost.Seconds(), nil
+}
+
+// WithCPUQuotaWarning makes Start() and StartPaused() check the ticker's tick
+// rate against ContainerAwareMaxRate(), and call onRisk with both rates if
+// the tick rate is higher.  The ticker is started at its rate regardless; to
+// cap it, Reset() it to IntervalForRate(maxRate).  onRisk is called after the
+// ticker has started, from the goroutine that started it.  If the quota
+// cannot be read, there is no warning.
+func WithCPUQuotaWarning(onRisk func(requestedRate, maxRate float64)) Option {
+	return func(config *tickerConfig) {
+		config.onCPUQuotaRisk = onRisk
+	}
+}
+
+// checkCPUQuota calls the onRisk of WithCPUQuotaWarning if the ticker's rate
+// exceeds ContainerAwareMaxRate().
+func (ticker *Ticker[T]) checkCPUQuota() {
+	ticker.mu.Lock()
+	requested := float64(time.Second) / float64(scaled(ticker.desiredInterval))
+	if ticker.rate != 0 {
+		requested = float64(scaledRate(ticker.rate)) / microTicksPerTick
+	}
+	ticker.mu.Unlock()
+
+	if maxRate, err := ContainerAwareMaxRate(); err == nil && requested > maxRate {
+		ticker.config.onCPUQuotaRisk(requested, maxRate)
+	}
+}
+
+// readCPUQuota implements CPUQuota, with the paths of /proc/self/cgroup and
+// the cgroup mount point as parameters.
+func readCPUQuota(procCgroup string, root string) (cpus float64, limited bool, err error) {
+	file, err := os.Open(procCgroup)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	var v1Path, v1Controllers, v2Path string
+	var isV1, isV2 bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Each line is hierarchy-ID:controller-list:cgroup-path.
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if fields[0] == "0" && fields[1] == "" {
+			v2Path, isV2 = fields[2], true
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			if controller == "cpu" {
+				v1Path, v1Controllers, isV1 = fields[2], fields[1], true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, false, err
+	}
+
+	switch {
+	case isV1:
+		return minQuota(v1Path, []string{path.Join(root, v1Controllers), path.Join(root, "cpu")}, readCFSQuota)
+	case isV2:
+		return minQuota(v2Path, []string{root}, readCPUMax)
+	default:
+		return 0, false, nil
+	}
+}
+
+// minQuota returns the smallest quota, read by readQuota, of the cgroup at
+// cgroupPath and of its ancestors, under the first of the mount points that
+// holds any of them.
+func minQuota(cgroupPath string, mounts []string, readQuota func(dir string) (float64, bool, error)) (cpus float64, limited bool, err error) {
+	for _, mount := range mounts {
+		found := false
+		for dir := path.Clean("/" + cgroupPath); ; dir = path.Dir(dir) {
+			quota, hasQuota, err := readQuota(path.Join(mount, dir))
+			if errors.Is(err, fs.ErrNotExist) {
+				if dir == "/" {
+					break
+				}
+				continue
+			}
+			if err != nil {
+				return 0, false, err
+			}
+
+			found = true
+			if hasQuota && (!limited || quota < cpus) {
+				cpus, limited = quota, true
+			}
+			if dir == "/" {
+				break
+			}
+		}
+		if found {
+			return cpus, limited, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// readCPUMax reads the quota from the cpu.max file of a cgroup v2 directory.
+func readCPUMax(dir string) (float64, bool, error) {
+	contents, err := os.ReadFile(path.Join(dir, "cpu.max"))
+	if err != nil {
+		return 0, false, err
+	}
+
+	fields := strings.Fields(string(contents))
+	if len(fields) == 0 || fields[0] == "max" {
+		return 0, false, nil
+	}
+
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false, err
+	}
+	period := 100000.0
+	if len(fields) > 1 {
+		if period, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return 0, false, err
+		}
+	}
+
+	return quota / period, period > 0, nil
+}
+
+// readCFSQuota reads the quota from the cpu.cfs_quota_us and
+// cpu.cfs_period_us files of a cgroup v1 directory.
+func readCFSQuota(dir string) (float64, bool, error) {
+	quota, err := readCgroupInt(path.Join(dir, "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false, err
+	}
+	if quota < 0 {
+		return 0, false, nil
+	}
+
+	period, err := readCgroupInt(path.Join(dir, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false, err
+	}
+	if period <= 0 {
+		return 0, false, nil
+	}
+
+	return float64(quota) / float64(period), true, nil
+}
+
+func readCgroupInt(name string) (int64, error) {
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(contents)), 10, 64)
+}
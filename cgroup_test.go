@@ -0,0 +1,100 @@
+package hrtime_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+// writeFiles creates the files, relative to dir, with their contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCPUQuota(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		files   map[string]string
+		cpus    float64
+		limited bool
+	}{
+		{
+			name: "v2",
+			files: map[string]string{
+				"proc":                                 "0::/kubepods/pod1/container\n",
+				"root/kubepods/pod1/container/cpu.max": "150000 100000\n",
+				"root/kubepods/pod1/cpu.max":           "50000 100000\n",
+				"root/kubepods/cpu.max":                "max 100000\n",
+			},
+			cpus:    0.5,
+			limited: true,
+		},
+		{
+			name: "v2 unlimited",
+			files: map[string]string{
+				"proc":         "0::/\n",
+				"root/cpu.max": "max 100000\n",
+			},
+		},
+		{
+			name: "v2 namespaced",
+			files: map[string]string{
+				"proc":         "0::/\n",
+				"root/cpu.max": "200000 100000\n",
+			},
+			cpus:    2,
+			limited: true,
+		},
+		{
+			name: "v1",
+			files: map[string]string{
+				"proc": "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n0::/docker/abc\n",
+				"root/cpu,cpuacct/docker/abc/cpu.cfs_quota_us":  "25000\n",
+				"root/cpu,cpuacct/docker/abc/cpu.cfs_period_us": "100000\n",
+				"root/cpu,cpuacct/cpu.cfs_quota_us":             "-1\n",
+				"root/cpu,cpuacct/cpu.cfs_period_us":            "100000\n",
+			},
+			cpus:    0.25,
+			limited: true,
+		},
+		{
+			name:  "no cgroup files",
+			files: map[string]string{"proc": "0::/user.slice\n"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, test.files)
+
+			cpus, limited, err := hrtime.ReadCPUQuota(filepath.Join(dir, "proc"), filepath.Join(dir, "root"))
+			if err != nil {
+				t.Fatalf("on CPUQuota(): %s", err.Error())
+			}
+			if cpus != test.cpus || limited != test.limited {
+				t.Errorf("expected %g CPUs (limited %t), got %g (limited %t)", test.cpus, test.limited, cpus, limited)
+			}
+		})
+	}
+}
+
+func TestContainerAwareMaxRate(t *testing.T) {
+	maxRate, err := hrtime.ContainerAwareMaxRate()
+	if err != nil {
+		t.Fatalf("on ContainerAwareMaxRate(): %s", err.Error())
+	}
+	if !(maxRate > 0) {
+		t.Errorf("expected a positive rate, got %g", maxRate)
+	}
+}
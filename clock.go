@@ -0,0 +1,323 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A MonotonicTime is a reading of CLOCK_MONOTONIC, expressed as the time
+// elapsed since the clock's (unspecified) starting point.  It is only
+// meaningful relative to other MonotonicTime values.
+//
+// Every MonotonicTime that the package reports -- the FiredAt of a RichTick,
+// LastTickAt(), the DetectedAt of a ClockStep or a Suspend -- is in this one
+// clock domain, whatever clock drives the ticker (WithClock only chooses when
+// the timer expires), so timestamps from different tickers can be compared
+// with one another, and with MonotonicNow(), to order events across them.
+// CLOCK_MONOTONIC_RAW is not used, although it is unaffected by NTP's
+// frequency corrections, because timers cannot be armed on it, so its
+// readings could not be compared with tick deadlines.  A TimestampSource
+// other than the default must keep to this domain; a TSCSource does, to
+// within its calibration drift.
+type MonotonicTime time.Duration
+
+// MonotonicNow returns the current reading of CLOCK_MONOTONIC.  Events
+// timestamped with it can be ordered against tick timestamps.
+func MonotonicNow() MonotonicTime {
+	now, _ := clockNow(unix.CLOCK_MONOTONIC)
+	return MonotonicTime(now)
+}
+
+// NowRaw returns the current reading in the clock domain of the package's
+// tick timestamps.  It is the same as MonotonicNow(): despite its name, it
+// reads CLOCK_MONOTONIC, not CLOCK_MONOTONIC_RAW, because timers cannot be
+// armed on the raw clock, and a reading of it could not be ordered against
+// the FiredAt of a tick, nor subtracted from it.  It is meant for callers
+// that timestamp their own events and want to be sure that they share the
+// ticks' domain.
+func NowRaw() MonotonicTime {
+	return MonotonicNow()
+}
+
+// Sub returns the duration m - earlier.
+func (m MonotonicTime) Sub(earlier MonotonicTime) time.Duration {
+	return time.Duration(m - earlier)
+}
+
+// Add returns the MonotonicTime m + d.
+func (m MonotonicTime) Add(d time.Duration) MonotonicTime {
+	return m + MonotonicTime(d)
+}
+
+func clockNow(clockID int32) (time.Duration, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(clockID, &ts); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ts.Nano()), nil
+}
+
+// The number of attempts made by sampleWallClockOffset.  The attempt with
+// the narrowest monotonic window is kept, which discards samples where the
+// goroutine was preempted between clock reads.
+const wallClockSampleAttempts = 3
+
+// sampleWallClockOffset returns CLOCK_REALTIME - CLOCK_MONOTONIC, and the
+// monotonic time at which the sample was taken.  The realtime reading is
+// bracketed by two monotonic readings, and the midpoint of those is used.
+func sampleWallClockOffset() (offset time.Duration, sampledAt MonotonicTime, err error) {
+	narrowestWindow := time.Duration(-1)
+
+	for i := 0; i < wallClockSampleAttempts; i++ {
+		before, err := clockNow(unix.CLOCK_MONOTONIC)
+		if err != nil {
+			return 0, 0, err
+		}
+		wall, err := clockNow(unix.CLOCK_REALTIME)
+		if err != nil {
+			return 0, 0, err
+		}
+		after, err := clockNow(unix.CLOCK_MONOTONIC)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if window := after - before; narrowestWindow < 0 || window < narrowestWindow {
+			narrowestWindow = window
+			midpoint := before + window/2
+			offset = wall - midpoint
+			sampledAt = MonotonicTime(midpoint)
+		}
+	}
+
+	return offset, sampledAt, nil
+}
+
+// A WallClockCorrelator maps MonotonicTime values (such as tick timestamps)
+// to wall-clock time.  It does this by sampling CLOCK_MONOTONIC and
+// CLOCK_REALTIME close together and recording the offset between them.
+// The offset drifts slowly (as NTP slews the realtime clock) and jumps when
+// the realtime clock is stepped, so it should be re-sampled periodically,
+// either explicitly with Resample() or automatically by Convert().
+type WallClockCorrelator struct {
+	resampleEvery time.Duration
+	mu            sync.Mutex
+	offset        time.Duration
+	sampledAt     MonotonicTime
+}
+
+// NewWallClockCorrelator creates a correlator and takes an initial sample.
+// If resampleEvery is greater than zero, Convert() takes a new sample when
+// the current one is older than resampleEvery.  Otherwise, the offset only
+// changes when Resample() is called.
+func NewWallClockCorrelator(resampleEvery time.Duration) (*WallClockCorrelator, error) {
+	c := &WallClockCorrelator{resampleEvery: resampleEvery}
+	if err := c.Resample(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Resample takes a new sample of the offset between the two clocks.
+func (c *WallClockCorrelator) Resample() error {
+	offset, sampledAt, err := sampleWallClockOffset()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.offset = offset
+	c.sampledAt = sampledAt
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Offset returns the most recently sampled value of CLOCK_REALTIME minus
+// CLOCK_MONOTONIC.
+func (c *WallClockCorrelator) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.offset
+}
+
+// Convert returns the wall-clock time corresponding to the monotonic time m,
+// re-sampling first if the current sample is stale.  If re-sampling fails,
+// the previous offset is used.
+func (c *WallClockCorrelator) Convert(m MonotonicTime) time.Time {
+	if c.resampleEvery > 0 {
+		c.mu.Lock()
+		stale := MonotonicNow().Sub(c.sampledAt) >= c.resampleEvery
+		c.mu.Unlock()
+
+		if stale {
+			c.Resample()
+		}
+	}
+
+	return time.Unix(0, int64(time.Duration(m)+c.Offset()))
+}
+
+// A ClockID identifies the kernel clock that drives a ticker.
+type ClockID int
+
+const (
+	// ClockMonotonic is a clock that cannot be set and is not affected by
+	// discontinuous jumps in the system time.  It does not advance while
+	// the system is suspended.  This is the default, unless it is changed
+	// by SetDefaultClock.
+	ClockMonotonic ClockID = unix.CLOCK_MONOTONIC
+
+	// ClockRealtime is the settable system-wide wall clock.  It follows
+	// UTC, so it is stepped by the operator or by NTP, and it repeats or
+	// skips a second at a leap second.
+	ClockRealtime ClockID = unix.CLOCK_REALTIME
+
+	// ClockBoottime is like ClockMonotonic, but it also advances while the
+	// system is suspended.
+	ClockBoottime ClockID = unix.CLOCK_BOOTTIME
+
+	// ClockTAI follows International Atomic Time.  It is derived from the
+	// realtime clock, but it is offset from UTC by the current number of
+	// leap seconds, so it has no leap-second discontinuities: a second is
+	// never repeated or skipped.  Precision-time (e.g., PTP) applications
+	// that must never observe a leap second should prefer it over
+	// ClockRealtime.  The TAI offset is only correct if something (such as
+	// ptp4l or chronyd) has told the kernel the current leap-second count.
+	// At the time of writing, mainline Linux does not accept CLOCK_TAI for
+	// timerfd, so on kernels without that support Start() returns an error
+	// wrapping ErrUnsupportedClock.
+	ClockTAI ClockID = unix.CLOCK_TAI
+)
+
+// defaultClockOverride holds the clock set by SetDefaultClock(), plus one, or
+// 0 while the default is ClockMonotonic.
+var defaultClockOverride atomic.Int64
+
+// SetDefaultClock changes the clock that tickers created from then on use
+// when they are not given WithClock (which still takes precedence), so that
+// a program, or a subsystem of one, can move all its tickers to, say,
+// ClockBoottime without changing each place that creates one.  It applies to
+// every constructor that takes options, such as NewMonotonicTicker and
+// NewRichTicker; tickers that have already been created keep their clock.
+// It is safe to call concurrently with the creation of tickers, whose clock
+// is then either the previous default or the new one.
+// SetDefaultClock(ClockMonotonic) restores the initial default.
+func SetDefaultClock(clock ClockID) {
+	if clock == ClockMonotonic {
+		defaultClockOverride.Store(0)
+		return
+	}
+
+	defaultClockOverride.Store(int64(clock) + 1)
+}
+
+// DefaultClock returns the clock set by SetDefaultClock(), which is
+// ClockMonotonic unless it has been changed.
+func DefaultClock() ClockID {
+	override := defaultClockOverride.Load()
+	if override == 0 {
+		return ClockMonotonic
+	}
+
+	return ClockID(override - 1)
+}
+
+// ErrUnsupportedClock is returned (wrapped) by Start() when the kernel
+// refuses to create a timer on the requested clock.
+var ErrUnsupportedClock = errors.New("clock is not supported for timers by this kernel")
+
+// String returns the name of the clock, as it is known to the kernel.
+func (clock ClockID) String() string {
+	switch clock {
+	case ClockMonotonic:
+		return "CLOCK_MONOTONIC"
+	case ClockRealtime:
+		return "CLOCK_REALTIME"
+	case ClockBoottime:
+		return "CLOCK_BOOTTIME"
+	case ClockTAI:
+		return "CLOCK_TAI"
+	default:
+		return fmt.Sprintf("ClockID(%d)", int(clock))
+	}
+}
+
+// MarshalText encodes the clock by its name, as returned by String().
+func (clock ClockID) MarshalText() ([]byte, error) {
+	return []byte(clock.String()), nil
+}
+
+// UnmarshalText decodes a clock name, as returned by String().
+func (clock *ClockID) UnmarshalText(text []byte) error {
+	for _, known := range []ClockID{ClockMonotonic, ClockRealtime, ClockBoottime, ClockTAI} {
+		if string(text) == known.String() {
+			*clock = known
+			return nil
+		}
+	}
+
+	var id int
+	if _, err := fmt.Sscanf(string(text), "ClockID(%d)", &id); err != nil {
+		return fmt.Errorf("unknown clock %q", text)
+	}
+	*clock = ClockID(id)
+
+	return nil
+}
+
+// SupportsInterval reports whether interval is at least the resolution of
+// the clock that a ticker created with opts would use (the default clock,
+// see SetDefaultClock, unless WithClock is among them), as clock_getres(2)
+// reports it, and
+// returns that resolution.  On a kernel with high-resolution timers the
+// resolution is 1ns; without them, it is a jiffy (1/HZ of a second), and
+// timers expire only on jiffy boundaries.  An interval below the resolution
+// can still be used, but each tick is then rounded to the resolution, so
+// the ticker's jitter is about as large as the resolution itself.  If the
+// resolution cannot be read, SupportsInterval returns false and a resolution
+// of 0.
+func SupportsInterval(interval time.Duration, opts ...Option) (bool, time.Duration) {
+	resolution, err := clockResolution(newTickerConfig(opts).clock)
+	if err != nil {
+		return false, 0
+	}
+
+	return interval >= resolution, resolution
+}
+
+// clockResolution returns the resolution of clock, as reported by
+// clock_getres(2).
+func clockResolution(clock ClockID) (time.Duration, error) {
+	var resolution unix.Timespec
+	if err := unix.ClockGetres(int32(clock), &resolution); err != nil {
+		return 0, fmt.Errorf("on clock_getres() of %s: %w", clock, err)
+	}
+
+	return time.Duration(resolution.Nano()), nil
+}
+
+// timerfdCreate creates a non-blocking timerfd on the provided clock,
+// translating the kernel's rejection of a clock into ErrUnsupportedClock.  The
+// timerfd is close-on-exec, so child processes started by os/exec (or
+// syscall.ForkExec) do not inherit it.
+func timerfdCreate(clock ClockID) (*os.File, error) {
+	fd, err := unix.TimerfdCreate(int(clock), unix.TFD_NONBLOCK|unix.TFD_CLOEXEC)
+	if err != nil {
+		if errors.Is(err, unix.EINVAL) {
+			return nil, fmt.Errorf("%w (%s): %w", ErrUnsupportedClock, clock, err)
+		}
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(fd), "timerfd"), nil
+}
@@ -0,0 +1,129 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestWallClockCorrelator(t *testing.T) {
+	correlator, err := hrtime.NewWallClockCorrelator(time.Second)
+	if err != nil {
+		t.Fatalf("on NewWallClockCorrelator(): %s", err.Error())
+	}
+
+	wallBefore := time.Now()
+	converted := correlator.Convert(hrtime.MonotonicNow())
+	wallAfter := time.Now()
+
+	if converted.Before(wallBefore.Add(-time.Millisecond)) || converted.After(wallAfter.Add(time.Millisecond)) {
+		t.Errorf("expected converted time to be between %s and %s, got %s", wallBefore, wallAfter, converted)
+	}
+
+	earlier := hrtime.MonotonicNow()
+	later := earlier.Add(250 * time.Millisecond)
+	if d := correlator.Convert(later).Sub(correlator.Convert(earlier)); d != 250*time.Millisecond {
+		t.Errorf("expected converted times to be 250ms apart, got %s", d)
+	}
+}
+
+func TestTickerClocks(t *testing.T) {
+	for _, clock := range []hrtime.ClockID{hrtime.ClockMonotonic, hrtime.ClockRealtime, hrtime.ClockBoottime} {
+		ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithClock(clock))
+		if err := ticker.Start(); err != nil {
+			t.Errorf("on Start() using %s: %s", clock, err.Error())
+			continue
+		}
+
+		if _, open := <-ticker.C; !open {
+			t.Errorf("on read using %s, channel is closed", clock)
+		}
+
+		ticker.Stop()
+	}
+
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithClock(hrtime.ClockTAI))
+	if err := ticker.Start(); err != nil {
+		if !errors.Is(err, hrtime.ErrUnsupportedClock) {
+			t.Errorf("on Start() using CLOCK_TAI, expected ErrUnsupportedClock, got (%s)", err.Error())
+		}
+		return
+	}
+
+	if _, open := <-ticker.C; !open {
+		t.Errorf("on read using CLOCK_TAI, channel is closed")
+	}
+	ticker.Stop()
+}
+
+func TestTickTimestampsShareOneDomain(t *testing.T) {
+	monotonic := hrtime.NewRichTicker(2 * time.Millisecond)
+	realtime := hrtime.NewRichTicker(3*time.Millisecond, hrtime.WithClock(hrtime.ClockRealtime))
+
+	before := hrtime.MonotonicNow()
+	if err := hrtime.StartAll([]*hrtime.RichTicker{monotonic, realtime}); err != nil {
+		t.Fatalf("on StartAll(): %s", err.Error())
+	}
+	defer hrtime.StopAll([]*hrtime.RichTicker{monotonic, realtime})
+
+	first, second := <-monotonic.C, <-realtime.C
+	after := hrtime.NowRaw()
+
+	for name, tick := range map[string]hrtime.RichTick{"CLOCK_MONOTONIC": first, "CLOCK_REALTIME": second} {
+		if tick.FiredAt < before || tick.FiredAt > after {
+			t.Errorf("expected the FiredAt of the %s ticker to fall between the MonotonicNow() and NowRaw() readings %d and %d, got %d", name, before, after, tick.FiredAt)
+		}
+	}
+}
+
+func TestSupportsInterval(t *testing.T) {
+	supported, resolution := hrtime.SupportsInterval(time.Millisecond)
+	if resolution <= 0 {
+		t.Fatalf("expected a positive clock resolution, got %s", resolution)
+	}
+	if supported != (time.Millisecond >= resolution) {
+		t.Errorf("expected SupportsInterval(1ms) to be %t with a resolution of %s", !supported, resolution)
+	}
+
+	if supported, _ := hrtime.SupportsInterval(resolution - 1); supported {
+		t.Errorf("expected an interval below the %s resolution not to be supported", resolution)
+	}
+	if supported, boottime := hrtime.SupportsInterval(resolution, hrtime.WithClock(hrtime.ClockBoottime)); boottime <= 0 || supported != (resolution >= boottime) {
+		t.Errorf("expected SupportsInterval() to report the resolution of CLOCK_BOOTTIME, got %s (%t)", boottime, supported)
+	}
+}
+
+func TestSetDefaultClock(t *testing.T) {
+	before := hrtime.NewMonotonicTicker(time.Millisecond)
+
+	hrtime.SetDefaultClock(hrtime.ClockBoottime)
+	defer hrtime.SetDefaultClock(hrtime.ClockMonotonic)
+	if clock := hrtime.DefaultClock(); clock != hrtime.ClockBoottime {
+		t.Fatalf("expected a default clock of CLOCK_BOOTTIME, got %s", clock)
+	}
+
+	for _, c := range []struct {
+		name     string
+		ticker   *hrtime.MonotonicTicker
+		expected hrtime.ClockID
+	}{
+		{"created before", before, hrtime.ClockMonotonic},
+		{"created after", hrtime.NewMonotonicTicker(time.Millisecond), hrtime.ClockBoottime},
+		{"with WithClock", hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithClock(hrtime.ClockRealtime)), hrtime.ClockRealtime},
+		{"with WithClock(ClockMonotonic)", hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithClock(hrtime.ClockMonotonic)), hrtime.ClockMonotonic},
+	} {
+		if clock := c.ticker.Inspect().Clock; clock != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, clock)
+		}
+		if cfg := c.ticker.Config(); cfg.Clock == nil || *cfg.Clock != c.expected {
+			t.Errorf("%s: expected Config() to record %s, got %v", c.name, c.expected, cfg.Clock)
+		}
+	}
+
+	hrtime.SetDefaultClock(hrtime.ClockMonotonic)
+	if clock := hrtime.NewMonotonicTicker(time.Millisecond).Inspect().Clock; clock != hrtime.ClockMonotonic {
+		t.Errorf("expected SetDefaultClock(ClockMonotonic) to restore CLOCK_MONOTONIC, got %s", clock)
+	}
+}
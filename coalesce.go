@@ -0,0 +1,41 @@
+package hrtime
+
+// WithCoalesceHandler makes the ticker call onCoalesce, instead of delivering
+// as usual, when the ticks that a delivery would carry number more than
+// threshold, so that a consumer that has fallen far behind can resynchronize
+// (skip its work, say, and start again from the current tick) rather than
+// catch up tick by tick.  onCoalesce is called with the count, and the ticks
+// are consumed by the call: no value is sent on the channel (or passed to the
+// handler) for them, and the next value counts only the ticks after them.
+// Ticks at or below the threshold are delivered as usual.
+//
+// Ticks coalesce when the consumer is not ready for a value, so the count
+// grows with each value that cannot be sent; onCoalesce can also be called
+// for a single read, if the read goroutine itself was held up long enough
+// (after the system was suspended, for instance).  With WithSkipMissed, only
+// the latter happens.  The call is not counted in Stats(), or by
+// DeliveredCount(), and it does not advance the Seq of a RichTick.  The
+// threshold of WithOverrunPause is never reached if it is not below
+// threshold.  onCoalesce is called from the read goroutine, so it must not
+// block for long.  A threshold of 0 (the default) disables this.
+func WithCoalesceHandler(threshold uint64, onCoalesce func(count uint64)) Option {
+	return func(config *tickerConfig) {
+		config.coalesceThreshold = threshold
+		config.onCoalesce = onCoalesce
+	}
+}
+
+// coalesced calls the coalesce handler, and consumes the ticks, if tick
+// carries more than the threshold of WithCoalesceHandler.  It reports whether
+// it did, in which case the ticks must not be delivered.
+func (ticker *Ticker[T]) coalesced(handles *tickerHandles[T], tick *tickInfo) bool {
+	threshold := ticker.config.coalesceThreshold
+	if threshold == 0 || ticker.config.onCoalesce == nil || tick.delta <= threshold || handles.isClosed() {
+		return false
+	}
+
+	ticker.config.onCoalesce(tick.delta)
+	tick.delta = 0
+
+	return true
+}
@@ -0,0 +1,50 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestCoalesceHandler(t *testing.T) {
+	coalesced := make(chan uint64, 2)
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewRichTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithCoalesceHandler(5, func(count uint64) {
+		coalesced <- count
+	}))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// A single read of more than the threshold goes to the handler.
+	timer := backend.LastTimer()
+	timer.Advance(10)
+	if count := <-coalesced; count != 10 {
+		t.Errorf("expected the coalesce handler to get 10 ticks, got %d", count)
+	}
+
+	// So do ticks that accumulate past it while nobody receives.
+	timer.WaitForReader()
+	timer.Advance(3)
+	timer.WaitForReader()
+	timer.Advance(3)
+	if count := <-coalesced; count != 6 {
+		t.Errorf("expected the coalesce handler to get 6 ticks, got %d", count)
+	}
+
+	// Ticks at or below the threshold are sent, counting only the ticks
+	// after the coalesced ones.  Blocking delivery waits for the receive.
+	timer.WaitForReader()
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+	timer.Advance(5)
+	tick := <-ticker.C
+	if tick.Delta != 5 || tick.Cumulative != 21 || tick.Seq != 1 {
+		t.Errorf("expected Delta 5, Cumulative 21 and Seq 1, got %+v", tick)
+	}
+	if delivered := ticker.DeliveredCount(); delivered != 1 {
+		t.Errorf("expected 1 delivery, got %d", delivered)
+	}
+}
@@ -0,0 +1,140 @@
+package hrtime
+
+import (
+	"sync"
+	"time"
+)
+
+// A CompatTicker is a ticker that delivers the time of each tick on C, as a
+// time.Ticker does.
+type CompatTicker = Ticker[time.Time]
+
+// NewCompatTicker creates a ticker that is intended to fire a tick near
+// every interval, and that delivers the time at which the most recent tick
+// was observed, like a time.Ticker.  As with a time.Ticker, if the receiver
+// falls behind, ticks are dropped rather than queued.  Unlike a time.Ticker,
+// the ticker must be started with Start().
+//
+// The delivered time.Time carries a monotonic clock reading, so delivered
+// times may be subtracted from each other and from time.Now() precisely.  It
+// is computed from the tick's MonotonicTime by taking time.Now() and
+// subtracting how long ago (on CLOCK_MONOTONIC) the tick was observed.
+func NewCompatTicker(interval time.Duration, opts ...Option) *CompatTicker {
+	return newTicker(interval, opts, func(tick tickInfo) time.Time {
+		return timeOfMonotonic(tick.firedAt)
+	})
+}
+
+// timeOfMonotonic converts m to a time.Time, including a monotonic clock
+// reading.
+func timeOfMonotonic(m MonotonicTime) time.Time {
+	now := time.Now()
+	return now.Add(-MonotonicNow().Sub(m))
+}
+
+// A StdTicker has the shape of a time.Ticker -- a C field, Stop() and Reset()
+// -- so that code that holds a *time.Ticker by its concrete API can switch to
+// a timerfd-backed ticker by changing only its construction.  It differs from
+// a time.Ticker in these ways:
+//
+//   - Its ticks are timed by the kernel, on the clock of its ticker (the
+//     default clock, see SetDefaultClock, unless WithClock is given), rather
+//     than by the Go runtime's timers, so they are not delayed by the
+//     runtime's timer granularity or by a busy scheduler.
+//   - The time sent on C is the time.Now() at which the read goroutine
+//     observed the tick, so it includes the tick's wake-up latency; like a
+//     time.Ticker's, it carries a monotonic clock reading.
+//   - As with a time.Ticker, C holds one tick, and ticks that find it full
+//     are dropped: they are not coalesced into a count.  A MonotonicTicker
+//     counts them.
+//   - NewStdTicker returns an error if the timer cannot be created, and
+//     Reset() silently leaves the ticker as it was if it cannot be re-armed,
+//     or, if it is stopped, restarted.
+//   - It holds a timerfd and a goroutine while it runs, so it must be
+//     stopped; it is not reclaimed when it becomes unreachable.
+//
+// As with a time.Ticker, Stop() does not close C, and Reset() restarts a
+// stopped ticker.
+type StdTicker struct {
+	// C is the channel on which the ticks are delivered.
+	C <-chan time.Time
+
+	// mu serializes Stop() and Reset(), so that a Reset() that finds the
+	// ticker stopped is the one to restart it.
+	mu     sync.Mutex
+	ticker *MonotonicTicker
+}
+
+// NewStdTicker creates and starts a StdTicker that ticks every d.  Like
+// time.NewTicker, it panics if d is not positive.  The opts are those of
+// NewMonotonicTicker, except that the ticker delivers through a handler of its
+// own, which replaces any provided with WithHandler.
+func NewStdTicker(d time.Duration, opts ...Option) (*StdTicker, error) {
+	if d <= 0 {
+		panic("non-positive interval for NewStdTicker")
+	}
+
+	c := make(chan time.Time, 1)
+	ticker := NewMonotonicTicker(d, withOptions(opts, WithHandler(func(uint64) {
+		select {
+		case c <- time.Now():
+		default:
+		}
+	}))...)
+	if err := ticker.Start(); err != nil {
+		return nil, err
+	}
+
+	return &StdTicker{C: c, ticker: ticker}, nil
+}
+
+// Stop turns off the ticker, and closes its timer.  No more ticks are sent,
+// but C is not closed.
+func (t *StdTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ticker.Stop()
+}
+
+// Reset changes the ticker's interval to d, and restarts the ticker if it is
+// stopped; either way, the next tick arrives d after the call.  Like
+// time.Ticker's Reset(), it panics if d is not positive.
+func (t *StdTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for StdTicker.Reset")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ticker := t.ticker
+	ticker.mu.Lock()
+	stopped := ticker.inStoppedState
+	previousInterval, previousRate := ticker.desiredInterval, ticker.rate
+	if err := ticker.resetInterval(d); err != nil {
+		ticker.mu.Unlock()
+		return
+	}
+	ticker.cancelBoost()
+	ticker.mu.Unlock()
+
+	if !stopped {
+		return
+	}
+
+	if err := ticker.Start(); err != nil {
+		ticker.mu.Lock()
+		if ticker.inStoppedState {
+			ticker.resetSchedule(previousInterval, previousRate)
+		}
+		ticker.mu.Unlock()
+	}
+}
+
+// Ticker returns the MonotonicTicker that drives t, for its statistics and
+// the like.  Starting or stopping it directly has the same effect as doing so
+// through t.
+func (t *StdTicker) Ticker() *MonotonicTicker {
+	return t.ticker
+}
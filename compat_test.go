@@ -0,0 +1,82 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestCompatTicker(t *testing.T) {
+	ticker := hrtime.NewCompatTicker(10 * time.Millisecond)
+
+	startedAt := time.Now()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	second := <-ticker.C
+
+	if elapsed := first.Sub(startedAt); elapsed < 10*time.Millisecond || elapsed > 50*time.Millisecond {
+		t.Errorf("expected first tick 10ms to 50ms after Start(), got %s", elapsed)
+	}
+	if interval := second.Sub(first); interval < 5*time.Millisecond || interval > 15*time.Millisecond {
+		t.Errorf("expected ticks about 10ms apart, got %s", interval)
+	}
+}
+
+func TestStdTicker(t *testing.T) {
+	ticker, err := hrtime.NewStdTicker(5 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("on NewStdTicker(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	first, second := <-ticker.C, <-ticker.C
+	if interval := second.Sub(first); interval < 2*time.Millisecond || interval > 20*time.Millisecond {
+		t.Errorf("expected ticks about 5ms apart, got %s", interval)
+	}
+
+	// Stop() does not close C, and Reset() restarts the ticker.
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case _, isOpen := <-ticker.C:
+		if !isOpen {
+			t.Fatalf("expected C to stay open after Stop()")
+		}
+		t.Errorf("expected no tick after Stop()")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	resetAt := time.Now()
+	ticker.Reset(10 * time.Millisecond)
+	if elapsed := (<-ticker.C).Sub(resetAt); elapsed < 10*time.Millisecond || elapsed > 50*time.Millisecond {
+		t.Errorf("expected a tick 10ms to 50ms after Reset(), got %s", elapsed)
+	}
+}
+
+func TestConstructorsLeaveCallerOptions(t *testing.T) {
+	// Options with room to spare in their backing array must not have the
+	// constructor's own options written into it.
+	opts := make([]hrtime.Option, 1, 2)
+	opts[0] = hrtime.WithSkipMissed()
+
+	ticker, err := hrtime.NewStdTicker(time.Second, opts...)
+	if err != nil {
+		t.Fatalf("on NewStdTicker(): %s", err.Error())
+	}
+	ticker.Stop()
+	hrtime.NewCountdownTicker(time.Second, 3, nil, opts...)
+	hrtime.NewDurationTicker(time.Second, time.Minute, opts...)
+	hrtime.NewHeartbeatTicker(time.Second, 10, opts...)
+
+	if spare := opts[:2][1]; spare != nil {
+		t.Errorf("expected the spare capacity of the caller's options to be left alone")
+	}
+}
@@ -0,0 +1,237 @@
+package hrtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Config describes a MonotonicTicker declaratively, so that tickers can be
+// loaded from configuration files.  Each field corresponds to a constructor
+// argument or an Option; the zero value of a field means that the option is
+// not used.  Durations are in nanoseconds, as time.Duration encodes them.
+// Options that require a callback (such as WithHandler or WithStepDetection)
+// or an object (WithBackend, WithTimestampSource, WithJitterSource and
+// WithBaseContext) cannot be expressed in a Config, nor can the options that
+// only concern a handler (such as WithHandlerPool) or an adopted descriptor
+// (WithRetainedFD); they are passed to NewTickerFromConfig alongside it
+// instead.  The options whose callbacks may be nil, such as WithCountdown,
+// are expressed without them.
+type Config struct {
+	// Interval is the interval between ticks.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+
+	// Clock, if it is not nil, is the clock that drives the ticker (see
+	// WithClock).  It is encoded by name, such as "CLOCK_BOOTTIME".  The
+	// default is ClockMonotonic, or the clock set by SetDefaultClock.
+	Clock *ClockID `json:"clock,omitempty" yaml:"clock,omitempty"`
+
+	// Backend selects the timer backend: "" or "timerfd" (the default),
+	// "posix" (WithPOSIXTimers) or "nanosleep" (WithNanosleep).  A ticker
+	// with a custom backend (see WithBackend) reports "", as the backend
+	// itself cannot be expressed, and must be passed to NewTickerFromConfig
+	// again.
+	Backend string `json:"backend,omitempty" yaml:"backend,omitempty"`
+
+	// Rate, if it is not zero, is the rate in ticks per second (see
+	// WithRate), and takes the place of Interval.
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+
+	ImmediateFirstTick    bool          `json:"immediateFirstTick,omitempty" yaml:"immediateFirstTick,omitempty"`
+	StartupJitter         time.Duration `json:"startupJitter,omitempty" yaml:"startupJitter,omitempty"`
+	SkipMissed            bool          `json:"skipMissed,omitempty" yaml:"skipMissed,omitempty"`
+	MinDeliveryInterval   time.Duration `json:"minDeliveryInterval,omitempty" yaml:"minDeliveryInterval,omitempty"`
+	OverrunPauseThreshold uint64        `json:"overrunPauseThreshold,omitempty" yaml:"overrunPauseThreshold,omitempty"`
+	Countdown             uint64        `json:"countdown,omitempty" yaml:"countdown,omitempty"`
+	Duration              time.Duration `json:"duration,omitempty" yaml:"duration,omitempty"`
+	ConsumerIdleTimeout   time.Duration `json:"consumerIdleTimeout,omitempty" yaml:"consumerIdleTimeout,omitempty"`
+	RingBufferSize        int           `json:"ringBufferSize,omitempty" yaml:"ringBufferSize,omitempty"`
+	Handoff               time.Duration `json:"handoff,omitempty" yaml:"handoff,omitempty"`
+	StopSentinel          bool          `json:"stopSentinel,omitempty" yaml:"stopSentinel,omitempty"`
+	SeparateSender        bool          `json:"separateSender,omitempty" yaml:"separateSender,omitempty"`
+	ExternalDriven        bool          `json:"externalDriven,omitempty" yaml:"externalDriven,omitempty"`
+	Heartbeat             uint64        `json:"heartbeat,omitempty" yaml:"heartbeat,omitempty"`
+	WarmupTicks           uint64        `json:"warmupTicks,omitempty" yaml:"warmupTicks,omitempty"`
+	WakeupLatency         bool          `json:"wakeupLatency,omitempty" yaml:"wakeupLatency,omitempty"`
+
+	// DeliveryMode is the delivery mode that the ticker starts with (see
+	// WithDeliveryMode), encoded by name, such as "BlockingDelivery".
+	DeliveryMode DeliveryMode `json:"deliveryMode,omitempty" yaml:"deliveryMode,omitempty"`
+
+	// AdaptiveEnterAbove, AdaptiveExitBelow and AdaptiveWindow are the
+	// arguments of WithAdaptiveDelivery, which is used if AdaptiveWindow is
+	// not zero.
+	AdaptiveEnterAbove float64       `json:"adaptiveEnterAbove,omitempty" yaml:"adaptiveEnterAbove,omitempty"`
+	AdaptiveExitBelow  float64       `json:"adaptiveExitBelow,omitempty" yaml:"adaptiveExitBelow,omitempty"`
+	AdaptiveWindow     time.Duration `json:"adaptiveWindow,omitempty" yaml:"adaptiveWindow,omitempty"`
+
+	// Strict enables WithStrictMode, and StopOnOverrun is its argument.
+	Strict        bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+	StopOnOverrun bool `json:"stopOnOverrun,omitempty" yaml:"stopOnOverrun,omitempty"`
+
+	// RecentIntervals, if it is not zero, is the number of intervals that
+	// RecentIntervals() keeps (see WithRecentIntervals) in place of the
+	// default of 64.  A negative count disables it.
+	RecentIntervals int `json:"recentIntervals,omitempty" yaml:"recentIntervals,omitempty"`
+}
+
+// Options returns the options that configure a ticker as cfg describes, or an
+// error if cfg.Backend is not recognized.
+func (cfg Config) Options() ([]Option, error) {
+	var opts []Option
+
+	if cfg.Clock != nil {
+		opts = append(opts, WithClock(*cfg.Clock))
+	}
+
+	switch cfg.Backend {
+	case "", "timerfd":
+	case "posix":
+		opts = append(opts, WithPOSIXTimers())
+	case "nanosleep":
+		opts = append(opts, WithNanosleep())
+	default:
+		return nil, fmt.Errorf("unknown backend %q", cfg.Backend)
+	}
+
+	if cfg.Rate != 0 {
+		opts = append(opts, WithRate(cfg.Rate))
+	}
+	if cfg.ImmediateFirstTick {
+		opts = append(opts, WithImmediateFirstTick())
+	}
+	if cfg.StartupJitter != 0 {
+		opts = append(opts, WithStartupJitter(cfg.StartupJitter))
+	}
+	if cfg.SkipMissed {
+		opts = append(opts, WithSkipMissed())
+	}
+	if cfg.MinDeliveryInterval != 0 {
+		opts = append(opts, WithMinDeliveryInterval(cfg.MinDeliveryInterval))
+	}
+	if cfg.OverrunPauseThreshold != 0 {
+		opts = append(opts, WithOverrunPause(cfg.OverrunPauseThreshold, nil))
+	}
+	if cfg.Countdown != 0 {
+		opts = append(opts, WithCountdown(cfg.Countdown, nil))
+	}
+	if cfg.Duration != 0 {
+		opts = append(opts, WithDuration(cfg.Duration))
+	}
+	if cfg.ConsumerIdleTimeout != 0 {
+		opts = append(opts, WithConsumerIdleTimeout(cfg.ConsumerIdleTimeout))
+	}
+	if cfg.RingBufferSize != 0 {
+		opts = append(opts, WithRingBuffer(cfg.RingBufferSize))
+	}
+	if cfg.Handoff != 0 {
+		opts = append(opts, WithHandoff(cfg.Handoff))
+	}
+	if cfg.StopSentinel {
+		opts = append(opts, WithStopSentinel())
+	}
+	if cfg.SeparateSender {
+		opts = append(opts, WithSeparateSender())
+	}
+	if cfg.ExternalDriven {
+		opts = append(opts, WithExternalDriven())
+	}
+	if cfg.Heartbeat != 0 {
+		opts = append(opts, WithHeartbeat(cfg.Heartbeat))
+	}
+	if cfg.WarmupTicks != 0 {
+		opts = append(opts, WithWarmupTicks(cfg.WarmupTicks))
+	}
+	if cfg.WakeupLatency {
+		opts = append(opts, WithWakeupLatency())
+	}
+	if cfg.DeliveryMode != NonBlockingDelivery {
+		opts = append(opts, WithDeliveryMode(cfg.DeliveryMode))
+	}
+	if cfg.AdaptiveWindow != 0 {
+		opts = append(opts, WithAdaptiveDelivery(cfg.AdaptiveEnterAbove, cfg.AdaptiveExitBelow, cfg.AdaptiveWindow, nil))
+	}
+	if cfg.Strict {
+		opts = append(opts, WithStrictMode(cfg.StopOnOverrun))
+	}
+	if cfg.RecentIntervals != 0 {
+		opts = append(opts, WithRecentIntervals(cfg.RecentIntervals))
+	}
+
+	return opts, nil
+}
+
+// NewTickerFromConfig creates a MonotonicTicker as cfg describes.  The opts
+// are applied after the options derived from cfg, so they can add callbacks,
+// or override cfg.
+func NewTickerFromConfig(cfg Config, opts ...Option) (*MonotonicTicker, error) {
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMonotonicTicker(cfg.Interval, append(cfgOpts, opts...)...), nil
+}
+
+// Config returns the Config that describes the ticker's current interval (as
+// changed by Reset()), clock (as changed by ResetClock()) and delivery mode
+// (as changed by SetDeliveryMode()), and the options it was created with.
+// Callbacks, objects and the other options that a Config cannot express are
+// not part of it, so NewTickerFromConfig(ticker.Config()) creates a ticker
+// without them.
+func (ticker *Ticker[T]) Config() Config {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	config := &ticker.config
+	cfg := Config{
+		Interval:              ticker.desiredInterval,
+		Rate:                  float64(ticker.rate) / microTicksPerTick,
+		ImmediateFirstTick:    config.immediateFirstTick,
+		StartupJitter:         config.startupJitterMax,
+		SkipMissed:            config.skipMissed,
+		MinDeliveryInterval:   config.minDeliveryInterval,
+		OverrunPauseThreshold: config.overrunPauseThreshold,
+		Countdown:             config.countdown,
+		Duration:              config.duration,
+		ConsumerIdleTimeout:   config.consumerIdleTimeout,
+		RingBufferSize:        config.ringSize,
+		Handoff:               config.handoffWait,
+		StopSentinel:          config.stopSentinel,
+		SeparateSender:        config.separateSender,
+		ExternalDriven:        config.externalDriven,
+		Heartbeat:             config.heartbeatEvery,
+		WarmupTicks:           config.warmupTicks,
+		WakeupLatency:         config.wakeupLatency,
+		DeliveryMode:          ticker.DeliveryMode(),
+		Strict:                config.strict,
+		StopOnOverrun:         config.stopOnOverrun,
+	}
+
+	if config.adaptiveWindow > 0 {
+		cfg.AdaptiveEnterAbove = config.adaptiveEnterAbove
+		cfg.AdaptiveExitBelow = config.adaptiveExitBelow
+		cfg.AdaptiveWindow = config.adaptiveWindow
+	}
+
+	switch {
+	case config.recentIntervals == defaultRecentIntervals:
+	case config.recentIntervals > 0:
+		cfg.RecentIntervals = config.recentIntervals
+	default:
+		cfg.RecentIntervals = -1
+	}
+
+	if clock := config.clock; clock != ClockMonotonic || DefaultClock() != ClockMonotonic {
+		cfg.Clock = &clock
+	}
+
+	switch config.backend.(type) {
+	case timerfdBackend:
+	case posixTimerBackend:
+		cfg.Backend = "posix"
+	case nanosleepBackend:
+		cfg.Backend = "nanosleep"
+	}
+
+	return cfg
+}
@@ -0,0 +1,141 @@
+package hrtime_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerConfigRoundTrip(t *testing.T) {
+	boottime := hrtime.ClockBoottime
+	cfg := hrtime.Config{
+		Interval:            5 * time.Millisecond,
+		Clock:               &boottime,
+		Backend:             "nanosleep",
+		SkipMissed:          true,
+		MinDeliveryInterval: 20 * time.Millisecond,
+		Countdown:           3,
+		Heartbeat:           2,
+		WarmupTicks:         1,
+		DeliveryMode:        hrtime.BlockingDelivery,
+		Strict:              true,
+		RecentIntervals:     -1,
+	}
+
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("on json.Marshal(): %s", err.Error())
+	}
+
+	var decoded hrtime.Config
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("on json.Unmarshal() of %s: %s", encoded, err.Error())
+	}
+	if !reflect.DeepEqual(decoded, cfg) {
+		t.Errorf("expected %s to decode to %+v, got %+v", encoded, cfg, decoded)
+	}
+
+	ticker, err := hrtime.NewTickerFromConfig(decoded)
+	if err != nil {
+		t.Fatalf("on NewTickerFromConfig(): %s", err.Error())
+	}
+	if extracted := ticker.Config(); !reflect.DeepEqual(extracted, cfg) {
+		t.Errorf("expected Config() to return %+v, got %+v", cfg, extracted)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	ticks := uint64(0)
+	for expirations := range ticker.C {
+		ticks += expirations
+	}
+	if ticks != 3 {
+		t.Errorf("expected the configured countdown of 3 ticks, got %d", ticks)
+	}
+}
+
+func TestTickerConfigReflectsChanges(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithRate(95))
+
+	if cfg := ticker.Config(); cfg.Rate != 95 || cfg.Clock != nil || cfg.Backend != "" {
+		t.Errorf("expected a rate of 95 with the default clock and backend, got %+v", cfg)
+	}
+
+	ticker.Reset(10 * time.Millisecond)
+	ticker.ResetClock(hrtime.ClockRealtime)
+	cfg := ticker.Config()
+	if cfg.Interval != 10*time.Millisecond || cfg.Rate != 0 {
+		t.Errorf("expected Config() to reflect Reset(), got %+v", cfg)
+	}
+	if cfg.Clock == nil || *cfg.Clock != hrtime.ClockRealtime {
+		t.Errorf("expected Config() to reflect ResetClock(), got %+v", cfg)
+	}
+
+	if _, err := hrtime.NewTickerFromConfig(hrtime.Config{Interval: time.Millisecond, Backend: "sundial"}); err == nil {
+		t.Errorf("expected error from NewTickerFromConfig() with an unknown backend")
+	}
+
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+	if cfg := ticker.Config(); cfg.DeliveryMode != hrtime.BlockingDelivery {
+		t.Errorf("expected Config() to reflect SetDeliveryMode(), got %+v", cfg)
+	}
+}
+
+func TestTickerConfigWithCustomBackend(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithSkipMissed())
+
+	cfg := ticker.Config()
+	if cfg.Backend != "" {
+		t.Errorf("expected a custom backend to be reported as \"\", got %q", cfg.Backend)
+	}
+
+	recreated, err := hrtime.NewTickerFromConfig(cfg, hrtime.WithBackend(backend))
+	if err != nil {
+		t.Fatalf("on NewTickerFromConfig() with the Config() of a ticker with a custom backend: %s", err.Error())
+	}
+	if err := recreated.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer recreated.Stop()
+
+	backend.LastTimer().Fire()
+	if expirations := <-recreated.C; expirations != 1 {
+		t.Errorf("expected 1 expiration from the recreated ticker, got %d", expirations)
+	}
+}
+
+func TestClockIDText(t *testing.T) {
+	for _, clock := range []hrtime.ClockID{hrtime.ClockMonotonic, hrtime.ClockRealtime, hrtime.ClockBoottime, hrtime.ClockTAI, hrtime.ClockID(42)} {
+		text, _ := clock.MarshalText()
+		var decoded hrtime.ClockID
+		if err := decoded.UnmarshalText(text); err != nil || decoded != clock {
+			t.Errorf("expected %q to decode to %s, got %s (error %v)", text, clock, decoded, err)
+		}
+	}
+
+	var clock hrtime.ClockID
+	if err := clock.UnmarshalText([]byte("CLOCK_SUNDIAL")); err == nil {
+		t.Errorf("expected error on UnmarshalText() of an unknown clock")
+	}
+}
+
+func TestDeliveryModeText(t *testing.T) {
+	for _, mode := range []hrtime.DeliveryMode{hrtime.NonBlockingDelivery, hrtime.BlockingDelivery} {
+		text, _ := mode.MarshalText()
+		var decoded hrtime.DeliveryMode
+		if err := decoded.UnmarshalText(text); err != nil || decoded != mode {
+			t.Errorf("expected %q to decode to %s, got %s (error %v)", text, mode, decoded, err)
+		}
+	}
+
+	var mode hrtime.DeliveryMode
+	if err := mode.UnmarshalText([]byte("EventualDelivery")); err == nil {
+		t.Errorf("expected error on UnmarshalText() of an unknown delivery mode")
+	}
+}
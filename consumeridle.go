@@ -0,0 +1,53 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrConsumerIdle is wrapped by the error with which a ticker that uses
+// WithConsumerIdleTimeout stops when nothing has received from its channel
+// for the timeout.
+var ErrConsumerIdle = errors.New("no value was received from the ticker's channel")
+
+// WithConsumerIdleTimeout makes the ticker stop itself if no value has been
+// received from its channel for idleTimeout, which protects against a
+// consumer that exits without calling Stop(): without it, the read goroutine
+// and the timer would live on, dropping ticks, until the process exits.  The
+// error that it stops with wraps ErrConsumerIdle; it is sent on the channel
+// returned by Errors(), and passed to OnStop() as the reason.
+//
+// The timeout is measured from the last value sent or, before the first, from
+// the time at which the timer was armed (by Start(), Resume() or Reset()),
+// and it is only checked when the ticker ticks, so it is exceeded by up to an
+// interval.  A consumer that is busy when a tick is sent misses that send, so
+// idleTimeout should be several intervals, and longer than the consumer
+// takes to process a value.  It does not apply to a ticker with a handler
+// (see WithHandler).  An idleTimeout of 0 (the default) disables it.
+func WithConsumerIdleTimeout(idleTimeout time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.consumerIdleTimeout = idleTimeout
+	}
+}
+
+// consumerIdle is called by the read loop after each read.  It stops the
+// ticker, and returns true, if nothing has been received from the channel for
+// the consumer idle timeout.
+func (ticker *Ticker[T]) consumerIdle(handles *tickerHandles[T], now MonotonicTime) bool {
+	idleTimeout := scaled(ticker.config.consumerIdleTimeout)
+	if idleTimeout <= 0 || ticker.config.handler != nil {
+		return false
+	}
+
+	idle := now.Sub(MonotonicTime(handles.lastSentAt.Load()))
+	if idle < idleTimeout {
+		return false
+	}
+
+	err := fmt.Errorf("%w for %s", ErrConsumerIdle, idle)
+	handles.reportError(err)
+	ticker.endRun(handles, err)
+
+	return true
+}
@@ -0,0 +1,51 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestConsumerIdleTimeout(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithConsumerIdleTimeout(30*time.Millisecond))
+
+	reasons := make(chan error, 1)
+	ticker.OnStop(func(reason error) { reasons <- reason })
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+	errs := ticker.Errors()
+
+	// While the consumer receives, the ticker keeps running.
+	for received := 0; received < 50; {
+		select {
+		case <-ticker.C:
+			received++
+		case reason := <-reasons:
+			t.Fatalf("expected the ticker to run while its channel is read, but it stopped: %v", reason)
+		}
+	}
+
+	// Then the consumer goes away.
+	select {
+	case reason := <-reasons:
+		if !errors.Is(reason, hrtime.ErrConsumerIdle) {
+			t.Errorf("expected the ticker to stop with ErrConsumerIdle, got %v", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the ticker to stop once its channel was no longer read")
+	}
+
+	if err := <-errs; !errors.Is(err, hrtime.ErrConsumerIdle) {
+		t.Errorf("expected ErrConsumerIdle on the error channel, got %v", err)
+	}
+	if _, isOpen := <-ticker.C; isOpen {
+		t.Errorf("expected the channel to be closed")
+	}
+	if err := ticker.Start(); err != nil {
+		t.Errorf("expected the ticker to be restartable after an idle stop, got %s", err.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package hrtime
+
+import (
+	"context"
+	"time"
+)
+
+// WithBaseContext ties the ticker to ctx.  Each run of the ticker has a
+// context derived from ctx, which carries its values (such as trace IDs) to
+// a handler set with WithContextHandler, and which is cancelled when the run
+// stops.  When ctx is done, the ticker is stopped, which closes its channel
+// and releases its timer, and Start() returns ctx.Err() from then on.
+// Stop() can still be called directly, before or after ctx is done, and
+// calling both is harmless.
+func WithBaseContext(ctx context.Context) Option {
+	return func(config *tickerConfig) {
+		config.baseContext = ctx
+	}
+}
+
+// WithContextHandler is like WithHandler, but handler also receives the
+// context of the run: it is derived from the context of WithBaseContext (or
+// from context.Background()), and is cancelled when the ticker stops, so a
+// handler that starts work for a tick can propagate the context's values to
+// that work and abandon it when the ticker stops.
+func WithContextHandler(handler func(ctx context.Context, expirations uint64)) Option {
+	return func(config *tickerConfig) {
+		config.handler = handler
+	}
+}
+
+// NewMonotonicTickerWithContext creates a MonotonicTicker as
+// NewMonotonicTicker does, with the WithBaseContext option, and starts it.
+// When ctx is done, the ticker is stopped.  If ctx is already done, no
+// ticker is returned, and the error is ctx.Err().
+func NewMonotonicTickerWithContext(ctx context.Context, interval time.Duration, opts ...Option) (*MonotonicTicker, error) {
+	ticker := NewMonotonicTicker(interval, withOptions(opts, WithBaseContext(ctx))...)
+	if err := ticker.Start(); err != nil {
+		return nil, err
+	}
+
+	return ticker, nil
+}
@@ -0,0 +1,105 @@
+package hrtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestNewMonotonicTickerWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticker, err := hrtime.NewMonotonicTickerWithContext(ctx, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("on NewMonotonicTickerWithContext(): %s", err.Error())
+	}
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a tick from the started ticker")
+	}
+
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, isOpen := <-ticker.C:
+			if isOpen {
+				continue
+			}
+		case <-timeout:
+			t.Fatalf("expected the channel to be closed once the context is cancelled")
+		}
+		break
+	}
+
+	if err := ticker.Stop(); err != nil {
+		t.Errorf("on Stop() after cancellation: %s", err.Error())
+	}
+	if err := ticker.Start(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Start() after cancellation to return context.Canceled, got %v", err)
+	}
+}
+
+func TestNewMonotonicTickerWithContextStoppedFirst(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ticker, err := hrtime.NewMonotonicTickerWithContext(ctx, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("on NewMonotonicTickerWithContext(): %s", err.Error())
+	}
+	if err := ticker.Stop(); err != nil {
+		t.Fatalf("on Stop(): %s", err.Error())
+	}
+	cancel()
+
+	if _, isOpen := <-ticker.C; isOpen {
+		t.Errorf("expected the channel to be closed after Stop()")
+	}
+
+	if _, err := hrtime.NewMonotonicTickerWithContext(ctx, 5*time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from a cancelled context, got %v", err)
+	}
+}
+
+type traceKey struct{}
+
+func TestWithBaseContext(t *testing.T) {
+	base, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+	defer cancel()
+
+	traces := make(chan any, 100)
+	runContexts := make(chan context.Context, 100)
+	ticker := hrtime.NewMonotonicTicker(5*time.Millisecond, hrtime.WithBaseContext(base), hrtime.WithContextHandler(func(ctx context.Context, expirations uint64) {
+		traces <- ctx.Value(traceKey{})
+		runContexts <- ctx
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	if trace := <-traces; trace != "trace-1" {
+		t.Errorf("expected the handler's context to carry the base context's values, got %v", trace)
+	}
+
+	runContext := <-runContexts
+	cancel()
+	if _, isOpen := <-ticker.C; isOpen {
+		t.Errorf("expected the channel to be closed once the base context is cancelled")
+	}
+	select {
+	case <-runContext.Done():
+	case <-time.After(time.Second):
+		t.Errorf("expected the run's context to be cancelled once the ticker stops")
+	}
+
+	if err := ticker.Start(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Start() with a cancelled base context to return context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package hrtime
+
+import "time"
+
+// WithCountdown makes the ticker stop itself after count ticks.  After the
+// final tick has been delivered (any ticks beyond count are discarded), the
+// ticker is stopped, onDone (if it is not nil) is called from the read
+// goroutine, and then the channel is closed, so a consumer that ranges over
+// the channel finishes after onDone returns.
+//
+// onDone is called exactly once for each run of the ticker that completes its
+// count.  If Stop() is called first, the run does not complete and onDone is
+// not called; if the run completes first, a racing Stop() does nothing.  The
+// ticker can be started again after it completes, and counts afresh.
+func WithCountdown(count uint64, onDone func()) Option {
+	return func(config *tickerConfig) {
+		config.countdown = count
+		config.onCountdownDone = onDone
+	}
+}
+
+// NewCountdownTicker creates a MonotonicTicker that ticks count times at the
+// provided interval, then calls onDone and closes its channel.  It is the
+// same as NewMonotonicTicker with the WithCountdown option.
+func NewCountdownTicker(interval time.Duration, count uint64, onDone func(), opts ...Option) *MonotonicTicker {
+	return NewMonotonicTicker(interval, withOptions(opts, WithCountdown(count, onDone))...)
+}
+
+// countdownExpirations limits the expirations just read so that the run's
+// cumulative count does not pass the ticker's countdown.
+func (ticker *Ticker[T]) countdownExpirations(tick *tickInfo, expirations uint64) uint64 {
+	if count := ticker.config.countdown; count > 0 && tick.cumulative+expirations > count {
+		return count - tick.cumulative
+	}
+
+	return expirations
+}
+
+// countdownComplete reports whether every tick of the ticker's countdown has
+// been delivered.
+func (ticker *Ticker[T]) countdownComplete(tick *tickInfo) bool {
+	count := ticker.config.countdown
+	return count > 0 && tick.cumulative == count && tick.delta == 0
+}
+
+// complete is called by the read loop when the countdown is complete.  It
+// stops the ticker, unless Stop() got there first, then calls onDone and
+// closes the handles.
+func (ticker *Ticker[T]) complete(handles *tickerHandles[T]) {
+	ticker.mu.Lock()
+	if ticker.inStoppedState || ticker.handles != handles {
+		// Stop() closes the handles.
+		ticker.mu.Unlock()
+		return
+	}
+	ticker.inStoppedState = true
+	ticker.startedAt = time.Time{}
+	ticker.cancelBoost()
+	ticker.mu.Unlock()
+
+	handles.finishLoop()
+	if onDone := ticker.config.onCountdownDone; onDone != nil {
+		onDone()
+	}
+
+	handles.closeWithReason(ErrCountdownComplete)
+}
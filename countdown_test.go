@@ -0,0 +1,85 @@
+package hrtime_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestCountdownTicker(t *testing.T) {
+	done := atomic.Uint64{}
+	ticker := hrtime.NewCountdownTicker(2*time.Millisecond, 5, func() {
+		done.Add(1)
+	})
+
+	for run := 1; run <= 2; run++ {
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() of run %d: %s", run, err.Error())
+		}
+
+		ticks := uint64(0)
+		for expirations := range ticker.C {
+			ticks += expirations
+		}
+
+		if ticks != 5 {
+			t.Errorf("on run %d, expected 5 ticks before the channel closed, got %d", run, ticks)
+		}
+		if calls := done.Load(); calls != uint64(run) {
+			t.Errorf("on run %d, expected onDone to have been called %d times before the channel closed, got %d", run, run, calls)
+		}
+	}
+}
+
+func TestCountdownTickerDiscardsExtraTicks(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	handled := uint64(0)
+	done := false
+	ticker := hrtime.NewCountdownTicker(time.Millisecond, 3, func() { done = true },
+		hrtime.WithBackend(backend), hrtime.WithHandler(func(expirations uint64) {
+			handled += expirations
+		}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	backend.LastTimer().Advance(10)
+	for range ticker.C {
+	}
+
+	if handled != 3 || !done {
+		t.Errorf("expected 3 ticks then onDone, got %d ticks and done = %t", handled, done)
+	}
+}
+
+func TestCountdownTickerRacingStop(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		calls := atomic.Uint64{}
+		ticker := hrtime.NewCountdownTicker(time.Millisecond, 1, func() {
+			calls.Add(1)
+		}, hrtime.WithHandler(func(uint64) {}))
+
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+		time.Sleep(time.Duration(i%3) * time.Millisecond)
+		ticker.Stop()
+
+		select {
+		case _, open := <-ticker.C:
+			if open {
+				t.Fatalf("expected no values on the channel of a ticker with a handler")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("channel was not closed after Stop()")
+		}
+		if n := calls.Load(); n > 1 {
+			t.Fatalf("expected onDone to be called at most once, got %d", n)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package hrtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// A DeliveryMode says what the read goroutine does when it has a value to
+// send on the channel and no receiver is ready.
+type DeliveryMode int32
+
+const (
+	// NonBlockingDelivery carries the ticks over to the next value sent
+	// (or, with WithSkipMissed, drops them), so the read goroutine never
+	// waits for the consumer.  This is the default.
+	NonBlockingDelivery DeliveryMode = iota
+
+	// BlockingDelivery makes the read goroutine wait until a receiver takes
+	// the value, so a slow consumer gets every value rather than
+	// coalesced ones.  While it waits, expirations accumulate in the timer,
+	// and are read (and sent, coalesced) once the value has been taken;
+	// heartbeats and other processing of the read loop wait too.  The wait
+	// ends when the ticker stops, although a value that a receiver was
+	// about to take as the ticker stopped may still be received before the
+	// channel is closed.
+	BlockingDelivery
+)
+
+// String returns the name of the mode, such as "BlockingDelivery".
+func (mode DeliveryMode) String() string {
+	switch mode {
+	case NonBlockingDelivery:
+		return "NonBlockingDelivery"
+	case BlockingDelivery:
+		return "BlockingDelivery"
+	default:
+		return fmt.Sprintf("DeliveryMode(%d)", int32(mode))
+	}
+}
+
+// MarshalText encodes the mode by name, as returned by String(), so that it
+// can be used in a Config.
+func (mode DeliveryMode) MarshalText() ([]byte, error) {
+	return []byte(mode.String()), nil
+}
+
+// UnmarshalText decodes a mode name, as returned by String().
+func (mode *DeliveryMode) UnmarshalText(text []byte) error {
+	for _, known := range []DeliveryMode{NonBlockingDelivery, BlockingDelivery} {
+		if string(text) == known.String() {
+			*mode = known
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown delivery mode %q", text)
+}
+
+// WithDeliveryMode sets the delivery mode that the ticker starts with, in
+// place of NonBlockingDelivery.  It is equivalent to calling
+// SetDeliveryMode() before the first Start().
+func WithDeliveryMode(mode DeliveryMode) Option {
+	return func(config *tickerConfig) {
+		config.deliveryMode = mode
+	}
+}
+
+// SetDeliveryMode changes how the ticker delivers values on its channel when
+// no receiver is ready.  It takes effect from the next value sent, and lasts
+// across Stop() and Start().  It does not apply to a ticker with a handler
+// (see WithHandler) or a ring buffer (see WithRingBuffer).  With
+// WithAdaptiveDelivery, the mode set is kept only until the ticker next
+// switches modes itself.
+func (ticker *Ticker[T]) SetDeliveryMode(mode DeliveryMode) {
+	ticker.deliveryMode.Store(int32(mode))
+}
+
+// DeliveryMode returns the ticker's current delivery mode.
+func (ticker *Ticker[T]) DeliveryMode() DeliveryMode {
+	return DeliveryMode(ticker.deliveryMode.Load())
+}
+
+// WithAdaptiveDelivery makes the ticker switch between delivery modes by
+// itself, according to how well its consumer keeps up: it runs with
+// NonBlockingDelivery while the consumer keeps up, switches to
+// BlockingDelivery when more than enterAbove of the values it attempted to
+// send over the last window found no receiver ready, so that the consumer
+// can catch up without losing values, and switches back once no more than
+// exitBelow of the values sent over a window had to wait for a receiver.
+// onSwitch, if it is not nil, is called from the read goroutine with the new
+// mode at each switch.
+//
+// Two things keep the ticker from flapping between modes: exitBelow should be
+// well below enterAbove (a consumer that is only just keeping up stays in
+// the mode it is in), and after each switch the ticker waits for a whole
+// window of sends in the new mode before it considers switching again.  The
+// window is divided into ten buckets, as for WithOverrunAlarm.  The
+// fractions are between 0 and 1; an enterAbove of 1 or more never
+// switches.  A window of 0 or less disables adaptive delivery.
+func WithAdaptiveDelivery(enterAbove float64, exitBelow float64, window time.Duration, onSwitch func(mode DeliveryMode)) Option {
+	return func(config *tickerConfig) {
+		config.adaptiveEnterAbove = enterAbove
+		config.adaptiveExitBelow = exitBelow
+		config.adaptiveWindow = window
+		config.onDeliveryModeSwitch = onSwitch
+	}
+}
+
+// An adaptiveDelivery tracks, for a run with WithAdaptiveDelivery, the
+// fraction of sends that found no receiver ready since the last switch of
+// modes.  It is only used by the read goroutine.
+type adaptiveDelivery struct {
+	enterAbove float64
+	exitBelow  float64
+	window     time.Duration
+	onSwitch   func(mode DeliveryMode)
+	missed     slidingRatio
+	since      MonotonicTime
+}
+
+// newAdaptiveDelivery returns nil if adaptive delivery is not configured.
+func newAdaptiveDelivery(config *tickerConfig) *adaptiveDelivery {
+	if config.adaptiveWindow <= 0 {
+		return nil
+	}
+
+	return &adaptiveDelivery{
+		enterAbove: config.adaptiveEnterAbove,
+		exitBelow:  config.adaptiveExitBelow,
+		window:     config.adaptiveWindow,
+		onSwitch:   config.onDeliveryModeSwitch,
+		missed:     newSlidingRatio(config.adaptiveWindow),
+	}
+}
+
+// adaptDelivery is called by the read loop after each attempt to send a
+// value on the channel, with whether the value found no receiver ready (and
+// was carried over, or waited for one), and switches the delivery mode if
+// adaptive delivery calls for it.
+func (ticker *Ticker[T]) adaptDelivery(handles *tickerHandles[T], at MonotonicTime, missed bool) {
+	adaptive := handles.adaptiveDelivery
+	if adaptive == nil {
+		return
+	}
+
+	if adaptive.since == 0 {
+		adaptive.since = at
+	}
+	observed := adaptive.missed.record(at, missed)
+	if at.Sub(adaptive.since) < scaled(adaptive.window) {
+		return
+	}
+
+	mode := ticker.DeliveryMode()
+	switch {
+	case mode == NonBlockingDelivery && observed > adaptive.enterAbove:
+		mode = BlockingDelivery
+	case mode == BlockingDelivery && observed <= adaptive.exitBelow:
+		mode = NonBlockingDelivery
+	default:
+		return
+	}
+
+	ticker.SetDeliveryMode(mode)
+	adaptive.missed.reset()
+	adaptive.since = at
+	if adaptive.onSwitch != nil {
+		adaptive.onSwitch(mode)
+	}
+}
+
+// WithHandoff makes the read goroutine wait, for at most wait, for a
+// receiver to take a value that finds none ready, before carrying its ticks
+// over as usual.  A send to a receiver that is already waiting is a direct
+// handoff, which makes the receiver runnable at once, so by default a
+// consumer that is waiting on the channel is woken with the least possible
+// delay; but a consumer that is busy, even if it is about to receive, misses
+// the value, and gets its ticks with the next one, an interval later.  With
+// a handoff wait, a consumer that is a little late still gets the tick
+// then, rather than an interval later.
+//
+// The cost is that the read goroutine is held up while it waits: the
+// expirations that occur meanwhile are read, coalesced, once it is done, so
+// wait should be well below the interval.  For the lowest latency, run with
+// GOMAXPROCS of at least 2, so that the read goroutine and the consumer can
+// run in parallel, and keep the consumer's work per tick short; locking the
+// consumer to its OS thread (with runtime.LockOSThread) can reduce the
+// variability of its wake-ups further.  BenchmarkHandoffLatency compares the
+// latency with and without a handoff wait.  A wait of 0 or less (the default)
+// disables it; BlockingDelivery, which waits without limit, takes
+// precedence.
+func WithHandoff(wait time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.handoffWait = wait
+	}
+}
+
+// sendBlocking sends value on the current shared channel, waiting for a
+// receiver if none is ready, as BlockingDelivery does, or for at most
+// timeout if it is positive, as WithHandoff does.  waited reports whether it
+// had to wait.  It returns sent false if the handles are closed before the
+// value is taken, or the timeout passes.  If SwapChannel() replaces the
+// channel while it waits, it waits on the new channel instead.
+//
+// The wait cannot hold the handles lock, so that Stop() and SwapChannel() are
+// not held up by it.  Instead, the channel that it waits on is recorded, and
+// closeChannel leaves that channel for sendBlocking to close once the wait
+// is over, so that it is never closed under a blocked send.
+func (c *tickerHandles[T]) sendBlocking(value T, timeout time.Duration) (sent bool, waited bool) {
+	var expired <-chan time.Time
+	for {
+		c.mu.Lock()
+		if c.areClosed {
+			c.mu.Unlock()
+			return false, waited
+		}
+
+		channel, changed := c.sharedChannel, c.channelChanged
+		select {
+		case channel <- value:
+			c.mu.Unlock()
+			return true, waited
+		default:
+		}
+		c.blockingSendOn = channel
+		c.mu.Unlock()
+
+		if !waited && timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+		waited = true
+
+		timedOut := false
+		select {
+		case channel <- value:
+			sent = true
+		case <-changed:
+		case <-expired:
+			timedOut = true
+		}
+
+		c.mu.Lock()
+		c.blockingSendOn = nil
+		if c.closeAfterBlockingSend {
+			c.closeAfterBlockingSend = false
+			close(channel)
+		}
+		c.mu.Unlock()
+
+		if sent || timedOut {
+			return sent, true
+		}
+	}
+}
+
+// closeChannel closes channel, which is, or was, the shared channel, with
+// the handles lock held, and wakes a blocked sendBlocking.  If sendBlocking
+// is waiting to send on channel, the closure is left to it.
+func (c *tickerHandles[T]) closeChannel(channel chan T) {
+	if channel == c.blockingSendOn {
+		c.closeAfterBlockingSend = true
+	} else {
+		close(channel)
+	}
+
+	close(c.channelChanged)
+	c.channelChanged = make(chan struct{})
+}
@@ -0,0 +1,107 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+// waitForLoopState waits until the ticker's read goroutine is in state.
+func waitForLoopState[T any](t *testing.T, ticker *hrtime.Ticker[T], state hrtime.LoopState) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); ticker.LoopState() != state; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the read goroutine to reach %s, it is %s", state, ticker.LoopState())
+		}
+	}
+}
+
+func TestBlockingDelivery(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if mode := ticker.DeliveryMode(); mode != hrtime.NonBlockingDelivery {
+		t.Errorf("expected NonBlockingDelivery by default, got %s", mode)
+	}
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// With nobody receiving, the read goroutine waits to send the first
+	// tick, while the next ones accumulate in the timer.
+	timer := backend.LastTimer()
+	timer.Advance(1)
+	waitForLoopState(t, ticker, hrtime.LoopSending)
+	timer.Advance(2)
+
+	if value := <-ticker.C; value != 1 {
+		t.Errorf("expected the first value to count 1 tick, got %d", value)
+	}
+	if value := <-ticker.C; value != 2 {
+		t.Errorf("expected the second value to count the 2 ticks that accumulated, got %d", value)
+	}
+
+	// SwapChannel() moves a waiting send to the new channel.
+	timer.Advance(1)
+	waitForLoopState(t, ticker, hrtime.LoopSending)
+	oldC, newC := ticker.SwapChannel()
+	if _, isOpen := <-oldC; isOpen {
+		t.Errorf("expected the old channel to be closed by SwapChannel()")
+	}
+	if value := <-newC; value != 1 {
+		t.Errorf("expected the waiting value on the new channel, got %d", value)
+	}
+
+	// Stop() ends a waiting send.
+	timer.Advance(1)
+	waitForLoopState(t, ticker, hrtime.LoopSending)
+	ticker.Stop()
+	ticker.WaitStopped()
+	for range newC {
+	}
+}
+
+func TestAdaptiveDelivery(t *testing.T) {
+	switches := make(chan hrtime.DeliveryMode, 2)
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithAdaptiveDelivery(0.5, 0.1, 20*time.Millisecond, func(mode hrtime.DeliveryMode) {
+		switches <- mode
+	}))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// Nothing receives, so every send misses, and the ticker switches to
+	// blocking delivery.
+	select {
+	case mode := <-switches:
+		if mode != hrtime.BlockingDelivery {
+			t.Fatalf("expected a switch to BlockingDelivery, got %s", mode)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a switch to BlockingDelivery while the consumer was away")
+	}
+	if mode := ticker.DeliveryMode(); mode != hrtime.BlockingDelivery {
+		t.Errorf("expected DeliveryMode() to report BlockingDelivery, got %s", mode)
+	}
+
+	// A consumer that keeps up lets it switch back.
+	for {
+		select {
+		case <-ticker.C:
+			continue
+		case mode := <-switches:
+			if mode != hrtime.NonBlockingDelivery {
+				t.Fatalf("expected a switch back to NonBlockingDelivery, got %s", mode)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected a switch back to NonBlockingDelivery once the consumer kept up")
+		}
+		break
+	}
+}
@@ -0,0 +1,130 @@
+package hrtime
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A DividedTicker ticks once for every n ticks of the ticker that it was
+// derived from (see Divide), as a clock divider does.  It has no timer of
+// its own: the source ticker's read goroutine counts its ticks for it.
+type DividedTicker struct {
+	// C receives, for each tick, the number of ticks since the previous
+	// value that was received: normally 1, or more if the receiver fell
+	// behind.  It is closed when the DividedTicker stops.
+	C <-chan uint64
+
+	channel chan uint64
+	n       uint64
+	detach  func(divided *DividedTicker)
+
+	// counted is the number of source ticks counted towards the next tick,
+	// and pending the ticks not yet received.  They, and stopped, are
+	// protected by the lock of the source run's handles.
+	counted uint64
+	pending uint64
+	stopped bool
+
+	stopOnce sync.Once
+}
+
+// Divide returns a DividedTicker that ticks on every nth tick of the
+// ticker's current run, counting from the call to Divide.  Its ticks share
+// the source ticker's timing: they are counted from the expirations that the
+// source's read goroutine reads, whether or not they are delivered by the
+// source, so a DividedTicker pauses while the source is paused, and follows
+// it through Reset() and the like.  A value that finds no receiver ready is
+// carried over to the next, as with a MonotonicTicker; the source's read
+// goroutine never waits for it.
+//
+// The DividedTicker stops, and closes C, when Stop() is called or the source
+// run ends (by Stop(), or otherwise); it is not restarted when the source is
+// started again.  Divide returns an error if n is 0 or the ticker is stopped.
+func (ticker *Ticker[T]) Divide(n uint64) (*DividedTicker, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("divisor must be positive")
+	}
+
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return nil, fmt.Errorf("ticker is stopped")
+	}
+
+	handles := ticker.handles
+	channel := make(chan uint64)
+	divided := &DividedTicker{
+		C:       channel,
+		channel: channel,
+		n:       n,
+		detach:  handles.detachDivided,
+	}
+
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	if handles.areClosed {
+		return nil, fmt.Errorf("ticker is stopped")
+	}
+	handles.dividedTickers = append(handles.dividedTickers, divided)
+
+	return divided, nil
+}
+
+// Stop stops the DividedTicker and closes C.  The source ticker is not
+// affected.  Stop can be called more than once.
+func (divided *DividedTicker) Stop() {
+	divided.stopOnce.Do(func() {
+		divided.detach(divided)
+	})
+}
+
+// count adds expirations source ticks, and sends the pending ticks if a
+// receiver is ready.
+func (divided *DividedTicker) count(expirations uint64) {
+	divided.counted += expirations
+	divided.pending += divided.counted / divided.n
+	divided.counted %= divided.n
+	if divided.pending == 0 {
+		return
+	}
+
+	select {
+	case divided.channel <- divided.pending:
+		divided.pending = 0
+	default:
+	}
+}
+
+func (divided *DividedTicker) stop() {
+	if !divided.stopped {
+		divided.stopped = true
+		close(divided.channel)
+	}
+}
+
+// countDivided passes expirations to the run's divided tickers.
+func (c *tickerHandles[T]) countDivided(expirations uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, divided := range c.dividedTickers {
+		divided.count(expirations)
+	}
+}
+
+// detachDivided stops divided, and removes it from the run's divided
+// tickers.
+func (c *tickerHandles[T]) detachDivided(divided *DividedTicker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, attached := range c.dividedTickers {
+		if attached == divided {
+			c.dividedTickers = append(c.dividedTickers[:i], c.dividedTickers[i+1:]...)
+			break
+		}
+	}
+	divided.stop()
+}
@@ -0,0 +1,58 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestDivide(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	if _, err := ticker.Divide(2); err == nil {
+		t.Errorf("expected Divide() of a stopped ticker to fail")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if _, err := ticker.Divide(0); err == nil {
+		t.Errorf("expected Divide(0) to fail")
+	}
+	divided, err := ticker.Divide(5)
+	if err != nil {
+		t.Fatalf("on Divide(): %s", err.Error())
+	}
+	stopped, err := ticker.Divide(5)
+	if err != nil {
+		t.Fatalf("on Divide(): %s", err.Error())
+	}
+	stopped.Stop()
+	if _, open := <-stopped.C; open {
+		t.Errorf("expected the channel of a stopped DividedTicker to be closed")
+	}
+
+	// The source's own channel is not read: the divided ticks do not depend
+	// on its deliveries.
+	var received uint64
+	for received < 3 {
+		select {
+		case ticks := <-divided.C:
+			received += ticks
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 3 divided ticks, got %d", received)
+		}
+	}
+	if expirations := ticker.Stats().Expirations; expirations < 5*received {
+		t.Errorf("expected at least %d source ticks for %d divided ticks, got %d", 5*received, received, expirations)
+	}
+
+	if err := ticker.Stop(); err != nil {
+		t.Fatalf("on Stop(): %s", err.Error())
+	}
+	for range divided.C {
+	}
+	divided.Stop()
+}
@@ -0,0 +1,151 @@
+package hrtime_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// BenchmarkDrainTimerfds compares two ways of draining many timerfds that
+// expire together: one goroutine per timerfd, each blocked in a read through
+// the runtime poller (as every Ticker is read), and one epoll loop that
+// waits for all of them and reads each ready timerfd in turn.  The timers
+// share an interval and a first expiration, so each timer interval is one
+// burst of count expirations.  An op is one expiration drained, and the
+// benchmark reports the CPU time (user and system) per op, and, for the
+// epoll loop, the mean number of timerfds ready per epoll_wait(2).  readv(2)
+// is no help to the epoll loop, since it reads from a single descriptor, so
+// it still makes one read(2) per ready timerfd.
+func BenchmarkDrainTimerfds(b *testing.B) {
+	const interval = time.Millisecond
+
+	for _, count := range []int{16, 256, 1024} {
+		b.Run(fmt.Sprintf("goroutines-%d", count), func(b *testing.B) {
+			fds := armTimerfds(b, count, interval)
+
+			var drained atomic.Int64
+			done := make(chan struct{})
+			target := int64(b.N)
+			cpuBefore := cpuTime(b)
+			b.ResetTimer()
+			for _, fd := range fds {
+				file := os.NewFile(uintptr(fd), "timerfd")
+				defer file.Close()
+
+				go func() {
+					buf := make([]byte, 8)
+					for {
+						if _, err := file.Read(buf); err != nil {
+							return
+						}
+						expirations := int64(binary.NativeEndian.Uint64(buf))
+						if total := drained.Add(expirations); total >= target && total-expirations < target {
+							close(done)
+						}
+					}
+				}()
+			}
+			<-done
+			b.StopTimer()
+
+			b.ReportMetric(float64((cpuTime(b)-cpuBefore).Nanoseconds())/float64(b.N), "cpu-ns/op")
+		})
+
+		b.Run(fmt.Sprintf("epoll-%d", count), func(b *testing.B) {
+			fds := armTimerfds(b, count, interval)
+			defer func() {
+				for _, fd := range fds {
+					unix.Close(fd)
+				}
+			}()
+
+			epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+			if err != nil {
+				b.Fatalf("on epoll_create1(): %s", err.Error())
+			}
+			defer unix.Close(epfd)
+			for _, fd := range fds {
+				event := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(fd)}
+				if err := unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, fd, &event); err != nil {
+					b.Fatalf("on epoll_ctl(): %s", err.Error())
+				}
+			}
+
+			events := make([]unix.EpollEvent, count)
+			buf := make([]byte, 8)
+			var drained, ready, waits int
+			cpuBefore := cpuTime(b)
+			b.ResetTimer()
+			for drained < b.N {
+				n, err := unix.EpollWait(epfd, events, -1)
+				if err == unix.EINTR {
+					continue
+				} else if err != nil {
+					b.Fatalf("on epoll_wait(): %s", err.Error())
+				}
+				waits++
+				ready += n
+
+				for _, event := range events[:n] {
+					if _, err := unix.Read(int(event.Fd), buf); err != nil {
+						if err == unix.EAGAIN {
+							continue
+						}
+						b.Fatalf("on read() of a timerfd: %s", err.Error())
+					}
+					drained += int(binary.NativeEndian.Uint64(buf))
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64((cpuTime(b)-cpuBefore).Nanoseconds())/float64(b.N), "cpu-ns/op")
+			b.ReportMetric(float64(ready)/float64(waits), "fds/wait")
+		})
+	}
+}
+
+// armTimerfds creates count non-blocking timerfds on CLOCK_MONOTONIC, armed
+// to expire at the same time, one interval from now, and every interval
+// after that.  It skips the benchmark if they cannot be created.
+func armTimerfds(b *testing.B, count int, interval time.Duration) []int {
+	var now unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &now); err != nil {
+		b.Fatalf("on clock_gettime(): %s", err.Error())
+	}
+	spec := unix.ItimerSpec{
+		Interval: unix.NsecToTimespec(interval.Nanoseconds()),
+		Value:    unix.NsecToTimespec(now.Nano() + interval.Nanoseconds()),
+	}
+
+	fds := make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, unix.TFD_NONBLOCK|unix.TFD_CLOEXEC)
+		if err != nil {
+			for _, fd := range fds {
+				unix.Close(fd)
+			}
+			b.Skipf("cannot create %d timerfds: %s", count, err.Error())
+		}
+		if err := unix.TimerfdSettime(fd, unix.TFD_TIMER_ABSTIME, &spec, nil); err != nil {
+			b.Fatalf("on timerfd_settime(): %s", err.Error())
+		}
+		fds = append(fds, fd)
+	}
+
+	return fds
+}
+
+// cpuTime returns the user and system CPU time of the process so far.
+func cpuTime(b *testing.B) time.Duration {
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &usage); err != nil {
+		b.Fatalf("on getrusage(): %s", err.Error())
+	}
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -0,0 +1,52 @@
+package hrtime
+
+import "time"
+
+// WithDuration makes the ticker stop itself once total has passed since it
+// was started, including any time spent paused.  Stopping closes the
+// channel, as Stop() does.  Ticks that fall due before then are delivered as
+// usual; when total is not a multiple of the interval, the final, partial
+// interval produces no tick.  (A tick that falls due at the same moment that
+// total passes may or may not be delivered.)  Each Start() begins a new
+// total, and a Stop() before total passes cancels it.
+func WithDuration(total time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.duration = total
+	}
+}
+
+// NewDurationTicker creates a MonotonicTicker that ticks at the provided
+// interval until totalDuration has passed since it was started, then closes
+// its channel.  It is the same as NewMonotonicTicker with the WithDuration
+// option.
+func NewDurationTicker(interval time.Duration, totalDuration time.Duration, opts ...Option) *MonotonicTicker {
+	return NewMonotonicTicker(interval, withOptions(opts, WithDuration(totalDuration))...)
+}
+
+// startDuration schedules (with the ticker lock held) the end of a run of a
+// ticker with a duration.  close() cancels it.
+func (ticker *Ticker[T]) startDuration(handles *tickerHandles[T]) {
+	if ticker.config.duration <= 0 {
+		return
+	}
+
+	handles.durationTimer = time.AfterFunc(scaled(ticker.config.duration), func() {
+		ticker.endRun(handles, ErrDurationElapsed)
+	})
+}
+
+// endRun stops the ticker, for reason (such as the end of its duration),
+// unless the run has already stopped.
+func (ticker *Ticker[T]) endRun(handles *tickerHandles[T], reason error) {
+	ticker.mu.Lock()
+	if ticker.inStoppedState || ticker.handles != handles {
+		ticker.mu.Unlock()
+		return
+	}
+	ticker.inStoppedState = true
+	ticker.startedAt = time.Time{}
+	ticker.cancelBoost()
+	ticker.mu.Unlock()
+
+	handles.closeWithReason(reason)
+}
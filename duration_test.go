@@ -0,0 +1,61 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestDurationTicker(t *testing.T) {
+	ticker := hrtime.NewDurationTicker(20*time.Millisecond, 110*time.Millisecond)
+	startedAt := time.Now()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	ticks := uint64(0)
+	for expirations := range ticker.C {
+		ticks += expirations
+	}
+	elapsed := time.Since(startedAt)
+
+	if ticks != 5 {
+		t.Errorf("expected 5 ticks in 110ms at 20ms, got %d", ticks)
+	}
+	if elapsed < 110*time.Millisecond || elapsed > 300*time.Millisecond {
+		t.Errorf("expected the channel to close after 110ms, closed after %s", elapsed)
+	}
+
+	// Each Start() begins a new total.
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on second Start(): %s", err.Error())
+	}
+	if _, isOpen := <-ticker.C; !isOpen {
+		t.Errorf("expected a restarted ticker to tick before its duration passes")
+	}
+	for range ticker.C {
+	}
+}
+
+func TestDurationTickerStoppedEarly(t *testing.T) {
+	ticker := hrtime.NewDurationTicker(5*time.Millisecond, 100*time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := ticker.Stop(); err != nil {
+		t.Fatalf("on Stop(): %s", err.Error())
+	}
+
+	// The cancelled duration of the first run must not stop the second.
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on second Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	time.Sleep(60 * time.Millisecond)
+	if ticker.RunningSince().IsZero() {
+		t.Errorf("expected the restarted ticker to keep running for its own duration")
+	}
+}
@@ -0,0 +1,42 @@
+package hrtime
+
+import "time"
+
+// WaitForReadLoop blocks until the read goroutine of the ticker's current run
+// has started, and is about to make its first read of the timer.  The ticker
+// must have been started.
+func WaitForReadLoop[T any](ticker *Ticker[T]) {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	<-handles.loopStarted
+}
+
+// ResetShutdown undoes Shutdown(), so that tickers can be started again.
+func ResetShutdown() {
+	activeRuns.mu.Lock()
+	activeRuns.shuttingDown = false
+	activeRuns.mu.Unlock()
+}
+
+// ReadCPUQuota is CPUQuota, reading /proc/self/cgroup from procCgroup and the
+// cgroup files from under root.
+var ReadCPUQuota = readCPUQuota
+
+// NewRunTaggedTicker creates a ticker that delivers, in place of a tick
+// count, the number of the run that the delivery belongs to (1 for the first
+// Start()).
+func NewRunTaggedTicker(interval time.Duration, opts ...Option) *Ticker[uint64] {
+	return newTicker(interval, opts, func(tick tickInfo) uint64 {
+		return tick.run
+	})
+}
+
+// CurrentRun returns the number of the ticker's most recent run.
+func CurrentRun[T any](ticker *Ticker[T]) uint64 {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	return ticker.runs
+}
@@ -0,0 +1,86 @@
+package hrtime
+
+import (
+	"fmt"
+	"os"
+)
+
+// WithExternalDriven makes the caller, rather than the ticker, read the
+// ticker's timer, so that the timerfd can be watched from the caller's own
+// poll(2) or epoll(7) set, while the ticker still does its bookkeeping: the
+// read goroutine does not read the timer, and instead waits for the caller
+// to report each read with NotifyRead().  Everything that follows a read --
+// Stats(), lateness and the rest, heartbeats, the detection options, the
+// re-arming of WithRate, deliveries on the channel or to the handler -- then
+// happens as usual, on the read goroutine.
+//
+// The contract is:
+//
+//   - The caller gets the descriptor with FD(), after each Start() (and
+//     after ResetClock(), which replaces it), and stops using it once the
+//     ticker stops.  It must not close or re-arm it.
+//   - The descriptor is non-blocking.  When it is readable, the caller reads
+//     8 bytes from it, which hold the expiration count as a uint64 in host
+//     byte order (see timerfd_create(2)).  A read that fails with EAGAIN
+//     consumed nothing, and must not be reported.
+//   - Each successful read is reported with NotifyRead(), with the count
+//     read, exactly once, and reads are reported in the order they were made.
+//     The kernel resets the count with each read, so a count that is not
+//     reported is lost, and one that is reported twice is counted twice.
+//   - Nothing else reads the descriptor: StopWithResidual() returns a
+//     residual of 0 with this option, rather than reading it.
+//
+// With a backend other than timerfd there is no descriptor, but NotifyRead()
+// still drives the bookkeeping, which is how a test can drive a ticker.
+func WithExternalDriven() Option {
+	return func(config *tickerConfig) {
+		config.externalDriven = true
+	}
+}
+
+// NotifyRead reports to a ticker with WithExternalDriven that the caller has
+// read expirations from its timer.  It waits until the read goroutine has
+// taken the count (but not until it has processed it), so it waits while a
+// handler, or a blocking delivery, holds the read goroutine up.  It returns an
+// error if the ticker is stopped, or stops while NotifyRead waits, in which
+// case the count is not processed, or if the ticker does not have the
+// option, or if expirations is 0.
+func (ticker *Ticker[T]) NotifyRead(expirations uint64) error {
+	if !ticker.config.externalDriven {
+		return fmt.Errorf("ticker is not externally driven")
+	}
+	if expirations == 0 {
+		return fmt.Errorf("a read of the timer reports at least one expiration")
+	}
+
+	ticker.mu.Lock()
+	handles, stopped := ticker.handles, ticker.inStoppedState
+	ticker.mu.Unlock()
+
+	if stopped {
+		return fmt.Errorf("ticker is stopped")
+	}
+
+	select {
+	case handles.externalReads <- expirations:
+		return nil
+	case <-handles.closed:
+		return fmt.Errorf("ticker is stopped")
+	}
+}
+
+// readTimer makes the read goroutine's read of timer or, for an externally
+// driven run, waits for the next count reported by NotifyRead().  Once the
+// handles are closed, it reports the timer as closed.
+func (c *tickerHandles[T]) readTimer(timer BackendTimer) (uint64, error) {
+	if c.externalReads == nil {
+		return timer.Read()
+	}
+
+	select {
+	case expirations := <-c.externalReads:
+		return expirations, nil
+	case <-c.closed:
+		return 0, os.ErrClosed
+	}
+}
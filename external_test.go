@@ -0,0 +1,78 @@
+package hrtime_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestExternalDriven(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithExternalDriven())
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+	if err := ticker.NotifyRead(1); err == nil {
+		t.Errorf("expected NotifyRead() of a stopped ticker to fail")
+	}
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// The ticker leaves the timer's expirations to the caller.
+	timer := backend.LastTimer()
+	timer.Advance(3)
+	if err := ticker.NotifyRead(3); err != nil {
+		t.Fatalf("on NotifyRead(): %s", err.Error())
+	}
+	if value := <-ticker.C; value != 3 {
+		t.Errorf("expected the reported count of 3, got %d", value)
+	}
+	if stats := ticker.Stats(); stats.Expirations != 3 {
+		t.Errorf("expected 3 expirations in Stats(), got %d", stats.Expirations)
+	}
+	if unread, _ := timer.ReadPending(); unread != 3 {
+		t.Errorf("expected the ticker not to have read the timer, but %d of 3 expirations were left", unread)
+	}
+
+	if err := ticker.NotifyRead(0); err == nil {
+		t.Errorf("expected NotifyRead(0) to fail")
+	}
+	if err := hrtime.NewMonotonicTicker(time.Second).NotifyRead(1); err == nil {
+		t.Errorf("expected NotifyRead() of a ticker that is not externally driven to fail")
+	}
+}
+
+func TestExternalDrivenTimerfd(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithExternalDriven())
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	fd, err := ticker.FD()
+	if err != nil {
+		t.Fatalf("on FD(): %s", err.Error())
+	}
+
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	if _, err := unix.Poll(fds, 5000); err != nil || fds[0].Revents&unix.POLLIN == 0 {
+		t.Fatalf("expected the timerfd to become readable, got %v", err)
+	}
+	buf := make([]byte, 8)
+	if _, err := unix.Read(fd, buf); err != nil {
+		t.Fatalf("on reading the timerfd: %s", err.Error())
+	}
+	expirations := binary.NativeEndian.Uint64(buf)
+
+	if err := ticker.NotifyRead(expirations); err != nil {
+		t.Fatalf("on NotifyRead(): %s", err.Error())
+	}
+	if value := <-ticker.C; value != expirations {
+		t.Errorf("expected the %d expirations read, got %d", expirations, value)
+	}
+}
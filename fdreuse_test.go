@@ -0,0 +1,124 @@
+package hrtime_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"golang.org/x/sys/unix"
+)
+
+// TestNoCrossTalkAfterFDReuse starts and stops tickers rapidly, so that the
+// kernel reuses their timerfd numbers, and checks that a stopped ticker
+// never touches the timer of a later one.
+func TestNoCrossTalkAfterFDReuse(t *testing.T) {
+	idle := hrtime.NewMonotonicTicker(time.Hour)
+	if err := idle.Start(); err != nil {
+		t.Fatalf("on Start() of the idle ticker: %s", err.Error())
+	}
+	defer idle.Stop()
+
+	seenFDs := make(map[int]bool)
+	reused := 0
+	var stale *hrtime.MonotonicTicker
+	for i := 0; i < 200; i++ {
+		ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() of ticker %d: %s", i, err.Error())
+		}
+
+		fd, err := ticker.FD()
+		if err != nil {
+			t.Fatalf("on FD() of ticker %d: %s", i, err.Error())
+		}
+		if seenFDs[fd] {
+			reused++
+		}
+		seenFDs[fd] = true
+
+		// Operations on the stopped ticker that last held a timerfd
+		// must not reach this ticker's timer, whatever its number.
+		if stale != nil {
+			if _, err := stale.FD(); err == nil {
+				t.Errorf("expected FD() of a stopped ticker to fail")
+			}
+			if residual, err := stale.StopWithResidual(); residual != 0 || err != nil {
+				t.Errorf("expected StopWithResidual() of a stopped ticker to do nothing, got %d, %v", residual, err)
+			}
+			stale.Stop()
+		}
+
+		select {
+		case _, isOpen := <-ticker.C:
+			if !isOpen {
+				t.Fatalf("ticker %d stopped on its own", i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no tick received from ticker %d", i)
+		}
+
+		var spec unix.ItimerSpec
+		if err := unix.TimerfdGettime(fd, &spec); err != nil || spec.Interval.Nano() != int64(time.Millisecond) {
+			t.Fatalf("expected fd %d to still be ticker %d's 1ms timer, got %v (%v)", fd, i, spec, err)
+		}
+
+		ticker.Stop()
+		if _, isOpen := <-ticker.C; isOpen {
+			t.Fatalf("expected ticker %d's channel to be closed by Stop()", i)
+		}
+		stale = ticker
+	}
+
+	if reused == 0 {
+		t.Logf("no timerfd number was reused, so cross-talk was not exercised")
+	}
+
+	select {
+	case <-idle.C:
+		t.Errorf("the idle ticker received a tick meant for another ticker")
+	default:
+	}
+	if fd, err := idle.FD(); err != nil {
+		t.Errorf("on FD() of the idle ticker: %s", err.Error())
+	} else {
+		var spec unix.ItimerSpec
+		if err := unix.TimerfdGettime(fd, &spec); err != nil || spec.Interval.Nano() != int64(time.Hour) {
+			t.Errorf("expected the idle ticker's timer to keep its 1h interval, got %v (%v)", spec, err)
+		}
+	}
+}
+
+// TestNoCrossTalkConcurrentChurn starts and stops tickers with distinct
+// intervals from several goroutines at once, and checks that each ticker's
+// timer keeps its own interval.
+func TestNoCrossTalkConcurrentChurn(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 1; g <= 4; g++ {
+		interval := time.Duration(g) * time.Millisecond
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				ticker := hrtime.NewMonotonicTicker(interval)
+				if err := ticker.Start(); err != nil {
+					t.Errorf("on Start(): %s", err.Error())
+					return
+				}
+
+				<-ticker.C
+				if fd, err := ticker.FD(); err != nil {
+					t.Errorf("on FD(): %s", err.Error())
+				} else {
+					var spec unix.ItimerSpec
+					if err := unix.TimerfdGettime(fd, &spec); err != nil || spec.Interval.Nano() != int64(interval) {
+						t.Errorf("expected fd %d to be a %s timer, got %v (%v)", fd, interval, spec, err)
+					}
+				}
+				ticker.Stop()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,45 @@
+package hrtime_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"golang.org/x/sys/unix"
+)
+
+func TestTimerfdIsNotInheritedByChildren(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	fd, err := ticker.FD()
+	if err != nil {
+		t.Fatalf("on FD(): %s", err.Error())
+	}
+
+	flags, err := unix.FcntlInt(uintptr(fd), unix.F_GETFD, 0)
+	if err != nil {
+		t.Fatalf("on fcntl(F_GETFD): %s", err.Error())
+	}
+	if flags&unix.FD_CLOEXEC == 0 {
+		t.Errorf("expected the timerfd to be close-on-exec")
+	}
+
+	lsPath, err := exec.LookPath("ls")
+	if err != nil {
+		t.Skip("ls is not available to list a child's file descriptors")
+	}
+
+	listing, err := exec.Command(lsPath, "-l", "/proc/self/fd").Output()
+	if err != nil {
+		t.Fatalf("on listing the child's file descriptors: %s", err.Error())
+	}
+	if strings.Contains(string(listing), "timerfd") {
+		t.Errorf("expected no timerfd in the child, got:\n%s", listing)
+	}
+}
@@ -0,0 +1,189 @@
+package hrtime
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// WithHandler makes the ticker call handler, from its read goroutine, each
+// time it ticks, instead of sending on its channel.  handler receives the
+// number of ticks since its previous call, which is more than one only if
+// the previous call (or the read goroutine) took longer than an interval.
+// The channel is still created by Start() and closed when the ticker stops,
+// but no values are sent on it.
+//
+// Because handler runs on the read goroutine, a slow handler delays tick
+// accounting; see WithHandlerStats and WithSlowHandlerWarning for ways to
+// measure this.
+func WithHandler(handler func(expirations uint64)) Option {
+	return func(config *tickerConfig) {
+		config.handler = func(_ context.Context, expirations uint64) {
+			handler(expirations)
+		}
+	}
+}
+
+// WithHandlerStats makes the ticker measure how long each call to its
+// handler (see WithHandler) takes.  The measurements are available from
+// HandlerStats().  When neither this nor WithSlowHandlerWarning is used,
+// handler calls are not timed at all.
+func WithHandlerStats() Option {
+	return func(config *tickerConfig) {
+		config.handlerStats = true
+	}
+}
+
+// WithSlowHandlerWarning makes the ticker call onSlow after any call to its
+// handler (see WithHandler) that takes longer than fraction of the ticker's
+// interval.  For example, a fraction of 0.8 warns when a handler uses more
+// than 80% of the time available to it.  onSlow is called from the read
+// goroutine, with the handler's execution time.
+func WithSlowHandlerWarning(fraction float64, onSlow func(elapsed time.Duration)) Option {
+	return func(config *tickerConfig) {
+		config.slowHandlerFraction = fraction
+		config.onSlowHandler = onSlow
+	}
+}
+
+// HandlerStats describes the execution time of a ticker's handler since the
+// ticker was last started.
+type HandlerStats struct {
+	// Calls is the number of completed handler calls.
+	Calls uint64
+
+	// Last, Mean and Max are the execution time of the most recent call,
+	// the mean execution time, and the longest execution time.
+	Last time.Duration
+	Mean time.Duration
+	Max  time.Duration
+}
+
+// HandlerStats returns the handler execution time measurements collected
+// since the ticker was last started.  It returns a zero HandlerStats if
+// WithHandlerStats was not used.
+func (ticker *Ticker[T]) HandlerStats() HandlerStats {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return HandlerStats{}
+	}
+
+	return handles.handlerTiming.stats()
+}
+
+// handlerTiming accumulates handler execution time measurements.
+type handlerTiming struct {
+	mu    sync.Mutex
+	calls uint64
+	last  time.Duration
+	total time.Duration
+	max   time.Duration
+}
+
+func (timing *handlerTiming) record(elapsed time.Duration) {
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	timing.calls++
+	timing.last = elapsed
+	timing.total += elapsed
+	if elapsed > timing.max {
+		timing.max = elapsed
+	}
+}
+
+func (timing *handlerTiming) stats() HandlerStats {
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	stats := HandlerStats{Calls: timing.calls, Last: timing.last, Max: timing.max}
+	if timing.calls > 0 {
+		stats.Mean = timing.total / time.Duration(timing.calls)
+	}
+
+	return stats
+}
+
+// WithHandlerPanicRecovery makes the ticker recover from a panic in any call
+// of its handler (see WithHandler), on the read goroutine or on a worker of
+// its pool (see WithHandlerPool), rather than let it crash the program.  Only
+// the call that panicked is abandoned: the panic is reported on Errors() as a
+// *HandlerPanicError, and the ticker goes on calling the handler for the
+// ticks that follow.  The ticks of the abandoned call are counted as
+// delivered.  A handler that panics has stopped partway, so it must leave its
+// own state consistent for this to be safe.
+func WithHandlerPanicRecovery() Option {
+	return func(config *tickerConfig) {
+		config.recoverHandlerPanics = true
+	}
+}
+
+// A HandlerPanicError reports a panic recovered from a call of a ticker's
+// handler (see WithHandlerPanicRecovery).
+type HandlerPanicError struct {
+	// Value is the value passed to panic().
+	Value any
+
+	// Stack is the stack trace of the handler's goroutine at the panic.
+	Stack []byte
+}
+
+func (err *HandlerPanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", err.Value)
+}
+
+// Unwrap returns Value, if it is an error.
+func (err *HandlerPanicError) Unwrap() error {
+	if wrapped, isError := err.Value.(error); isError {
+		return wrapped
+	}
+
+	return nil
+}
+
+// runHandler calls the ticker's handler, timing the call if handler stats or
+// slow handler warnings are enabled.
+func (ticker *Ticker[T]) runHandler(handles *tickerHandles[T], expirations uint64) {
+	config := &ticker.config
+	if !config.handlerStats && config.onSlowHandler == nil {
+		ticker.callHandler(handles, expirations)
+		return
+	}
+
+	startedAt := MonotonicNow()
+	ticker.callHandler(handles, expirations)
+	elapsed := MonotonicNow().Sub(startedAt)
+
+	if config.handlerStats {
+		handles.handlerTiming.record(elapsed)
+	}
+
+	if config.onSlowHandler != nil {
+		handles.mu.Lock()
+		interval := handles.schedule.interval
+		handles.mu.Unlock()
+
+		if float64(elapsed) > config.slowHandlerFraction*float64(interval) {
+			config.onSlowHandler(elapsed)
+		}
+	}
+}
+
+// callHandler calls the ticker's handler, recovering from a panic in it if
+// WithHandlerPanicRecovery is used.
+func (ticker *Ticker[T]) callHandler(handles *tickerHandles[T], expirations uint64) {
+	if ticker.config.recoverHandlerPanics {
+		defer func() {
+			if value := recover(); value != nil {
+				handles.reportError(&HandlerPanicError{Value: value, Stack: debug.Stack()})
+			}
+		}()
+	}
+
+	ticker.config.handler(handles.ctx, expirations)
+}
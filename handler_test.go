@@ -0,0 +1,97 @@
+package hrtime_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerHandlerStats(t *testing.T) {
+	calls := atomic.Uint64{}
+	done := make(chan struct{})
+	slowWarnings := atomic.Uint64{}
+
+	handler := func(expirations uint64) {
+		time.Sleep(3 * time.Millisecond)
+		if calls.Add(1) == 10 {
+			close(done)
+		}
+	}
+
+	ticker := hrtime.NewMonotonicTicker(5*time.Millisecond,
+		hrtime.WithHandler(handler),
+		hrtime.WithHandlerStats(),
+		hrtime.WithSlowHandlerWarning(0.5, func(elapsed time.Duration) {
+			slowWarnings.Add(1)
+		}),
+	)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("handler was not called 10 times")
+	}
+
+	stats := ticker.HandlerStats()
+	ticker.Stop()
+
+	if stats.Calls < 10 {
+		t.Errorf("expected at least 10 calls in stats, got %d", stats.Calls)
+	}
+	if stats.Mean < 3*time.Millisecond || stats.Max < stats.Mean || stats.Last < 3*time.Millisecond {
+		t.Errorf("expected Last and Mean >= 3ms and Max >= Mean, got Last = %s, Mean = %s, Max = %s", stats.Last, stats.Mean, stats.Max)
+	}
+	if slowWarnings.Load() < 9 {
+		t.Errorf("expected a slow handler warning for each call, got %d warnings", slowWarnings.Load())
+	}
+
+	if _, open := <-ticker.C; open {
+		t.Errorf("expected no values to be sent on the channel of a ticker with a handler")
+	}
+}
+
+func TestTickerHandlerPanicRecovery(t *testing.T) {
+	for _, opts := range [][]hrtime.Option{nil, {hrtime.WithHandlerPool(1, 4, hrtime.QueueWhenBusy)}} {
+		var count atomic.Uint64
+		calls := make(chan uint64, 4)
+		handler := func(expirations uint64) {
+			calls <- expirations
+			if count.Add(1) == 3 {
+				panic("third tick")
+			}
+		}
+
+		backend := hrtimetest.NewBackend()
+		ticker := hrtime.NewMonotonicTicker(time.Second, append(opts, hrtime.WithBackend(backend), hrtime.WithHandler(handler), hrtime.WithHandlerPanicRecovery())...)
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+
+		timer := backend.LastTimer()
+		// The fourth tick is handled after the third panics.
+		for i := 0; i < 4; i++ {
+			timer.Fire()
+			timer.WaitForReader()
+			<-calls
+		}
+
+		select {
+		case err := <-ticker.Errors():
+			var panicErr *hrtime.HandlerPanicError
+			if !errors.As(err, &panicErr) || panicErr.Value != "third tick" || len(panicErr.Stack) == 0 {
+				t.Errorf("expected a HandlerPanicError for the third tick, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the panic to be reported")
+		}
+		ticker.Stop()
+	}
+}
@@ -0,0 +1,79 @@
+package hrtime
+
+// A BusyPolicy says what a ticker with a handler pool (see WithHandlerPool)
+// does with a tick when every worker is busy and the queue is full.
+type BusyPolicy int
+
+const (
+	// DropWhenBusy skips the handler call.  Its ticks are not lost: they
+	// are added to the next call that is dispatched (or, with
+	// WithSkipMissed, discarded).  The read goroutine never waits.
+	DropWhenBusy BusyPolicy = iota
+
+	// QueueWhenBusy makes the read goroutine wait until the call can be
+	// queued.  While it waits, expirations accumulate in the timer, and are
+	// reported together when it resumes.
+	QueueWhenBusy
+)
+
+// WithHandlerPool makes a ticker with a handler (see WithHandler) call it
+// from a pool of worker goroutines (workers of them) instead of its read
+// goroutine, so that a handler that blocks does not stall tick accounting.  Calls that no
+// worker is free to take are queued, up to queueLength calls; beyond that,
+// whenBusy decides what happens.  The workers are started by Start(), and
+// exit once the read goroutine has stopped and they have finished their
+// queued calls, so a handler can still be running after Stop() returns (and
+// after the onDone of WithCountdown has been called).
+//
+// Calls on different workers run concurrently, so handler must be safe for
+// concurrent use, and there is no ordering guarantee across workers: a call
+// for later ticks can start, or finish, before a call for earlier ones.
+// HandlerStats() and WithSlowHandlerWarning measure each call on the worker
+// that makes it.  A workers count of 0 or less disables the pool.
+func WithHandlerPool(workers int, queueLength int, whenBusy BusyPolicy) Option {
+	return func(config *tickerConfig) {
+		config.handlerWorkers = workers
+		config.handlerQueueLength = queueLength
+		config.whenHandlersBusy = whenBusy
+	}
+}
+
+// startHandlerPool creates the queue of handler calls for a run (if the
+// ticker uses a handler pool), and starts its workers.  The read loop closes
+// the queue when it exits.
+func (ticker *Ticker[T]) startHandlerPool(handles *tickerHandles[T]) {
+	config := &ticker.config
+	if config.handler == nil || config.handlerWorkers <= 0 {
+		return
+	}
+
+	handles.handlerCalls = make(chan uint64, max(config.handlerQueueLength, 0))
+	for i := 0; i < config.handlerWorkers; i++ {
+		go func() {
+			for expirations := range handles.handlerCalls {
+				ticker.runHandler(handles, expirations)
+			}
+		}()
+	}
+}
+
+// dispatchHandler queues a handler call for expirations.  It returns false
+// if the call was dropped, according to the BusyPolicy, or abandoned,
+// because the ticker stopped while it waited to be queued.
+func (ticker *Ticker[T]) dispatchHandler(handles *tickerHandles[T], expirations uint64) bool {
+	if ticker.config.whenHandlersBusy == QueueWhenBusy {
+		select {
+		case handles.handlerCalls <- expirations:
+			return true
+		case <-handles.closed:
+			return false
+		}
+	}
+
+	select {
+	case handles.handlerCalls <- expirations:
+		return true
+	default:
+		return false
+	}
+}
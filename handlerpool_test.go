@@ -0,0 +1,146 @@
+package hrtime_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+// blockingHandler is a handler that counts the ticks it is called with, and
+// blocks until it is released.
+type blockingHandler struct {
+	started  chan struct{}
+	release  chan struct{}
+	ticks    atomic.Uint64
+	finished sync.WaitGroup
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (handler *blockingHandler) handle(expirations uint64) {
+	handler.finished.Add(1)
+	defer handler.finished.Done()
+
+	handler.ticks.Add(expirations)
+	handler.started <- struct{}{}
+	<-handler.release
+}
+
+func TestTickerHandlerPoolDropWhenBusy(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	handler := newBlockingHandler()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend),
+		hrtime.WithHandler(handler.handle), hrtime.WithHandlerPool(2, 0, hrtime.DropWhenBusy))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// Fire until both workers are busy.  Calls that no worker was ready to
+	// take are dropped, and their ticks are added to later calls.
+	timer := backend.LastTimer()
+	fired := uint64(0)
+	for busy := 0; busy < 2; {
+		timer.Fire()
+		fired++
+		timer.WaitForReader()
+		select {
+		case <-handler.started:
+			busy++
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	dropsBefore := ticker.Stats().Drops
+	timer.Advance(3)
+	fired += 3
+	timer.WaitForReader()
+	if drops := ticker.Stats().Drops; drops != dropsBefore+1 {
+		t.Errorf("expected 1 more drop while every worker was busy, got %d", drops-dropsBefore)
+	}
+
+	close(handler.release)
+	for handler.ticks.Load() < fired {
+		timer.Fire()
+		fired++
+		timer.WaitForReader()
+		time.Sleep(time.Millisecond)
+	}
+	ticker.Stop()
+	handler.finished.Wait()
+
+	if ticks := handler.ticks.Load(); ticks != fired {
+		t.Errorf("expected handlers to be called with all %d ticks, got %d", fired, ticks)
+	}
+}
+
+func TestTickerHandlerPoolQueueWhenBusy(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	handler := newBlockingHandler()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend),
+		hrtime.WithHandler(handler.handle), hrtime.WithHandlerPool(1, 1, hrtime.QueueWhenBusy))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.Fire()
+	<-handler.started
+
+	// The second call is queued, and the read goroutine waits to queue the
+	// third, while the last two expirations accumulate in the timer.
+	for i := 0; i < 4; i++ {
+		timer.Fire()
+		time.Sleep(time.Millisecond)
+	}
+
+	close(handler.release)
+	timer.WaitForReader()
+	for handler.ticks.Load() < 5 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if stats := ticker.Stats(); stats.Drops != 0 || stats.Expirations != 5 {
+		t.Errorf("expected 5 expirations and no drops, got %+v", stats)
+	}
+}
+
+func TestTickerHandlerPoolQueueWhenBusyAbandonedOnStop(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	handler := newBlockingHandler()
+	defer close(handler.release)
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend),
+		hrtime.WithHandler(handler.handle), hrtime.WithHandlerPool(1, 0, hrtime.QueueWhenBusy))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	timer := backend.LastTimer()
+	timer.Fire()
+	<-handler.started
+
+	// The read goroutine waits to queue the second call, which Stop()
+	// abandons: it is a drop, not a delivery.
+	timer.Fire()
+	time.Sleep(10 * time.Millisecond)
+	ticker.Stop()
+
+	for deadline := time.Now().Add(5 * time.Second); ticker.Stats().Drops == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the abandoned call to be counted as a drop, got %+v", ticker.Stats())
+		}
+	}
+	if stats := ticker.Stats(); stats.Deliveries != 1 || stats.Drops != 1 {
+		t.Errorf("expected 1 delivery and 1 drop, got %+v", stats)
+	}
+}
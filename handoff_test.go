@@ -0,0 +1,101 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestHandoff(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithHandoff(time.Hour))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// A receiver that arrives while the read goroutine waits gets the tick.
+	timer := backend.LastTimer()
+	timer.Advance(1)
+	waitForLoopState(t, ticker, hrtime.LoopSending)
+	if value := <-ticker.C; value != 1 {
+		t.Errorf("expected the handed-off value to count 1 tick, got %d", value)
+	}
+	if stats := ticker.Stats(); stats.Drops != 0 {
+		t.Errorf("expected no drops, got %d", stats.Drops)
+	}
+}
+
+func TestHandoffTimesOut(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithHandoff(5*time.Millisecond))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// With nobody receiving, the wait ends, and the tick is carried over.
+	timer := backend.LastTimer()
+	timer.Advance(1)
+	for deadline := time.Now().Add(5 * time.Second); ticker.Stats().Drops == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the handoff wait to end in a drop")
+		}
+	}
+
+	timer.Advance(1)
+	if value := <-ticker.C; value != 2 {
+		t.Errorf("expected the carried-over tick with the next one, got %d", value)
+	}
+}
+
+// BenchmarkHandoffLatency reports how long after its nominal time the oldest
+// tick of each value is received, with and without a handoff wait, by a
+// consumer whose work on every other tick is a little longer than the
+// interval.  Without a handoff wait, the consumer misses the value after each
+// long piece of work, and gets its ticks an interval late.  The consumer
+// spins while it works, so the comparison is only meaningful with at least 2
+// CPUs.
+func BenchmarkHandoffLatency(b *testing.B) {
+	const interval = 100 * time.Microsecond
+
+	for _, mode := range []struct {
+		name string
+		opts []hrtime.Option
+	}{
+		{"nonblocking", nil},
+		{"handoff", []hrtime.Option{hrtime.WithHandoff(interval / 2)}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			ticker := hrtime.NewRichTicker(interval, mode.opts...)
+			if err := ticker.Start(); err != nil {
+				b.Fatalf("on Start(): %s", err.Error())
+			}
+			defer ticker.Stop()
+
+			var total, greatest time.Duration
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tick := <-ticker.C
+				receivedAt := hrtime.MonotonicNow()
+				oldest := tick.FiredAt.Add(-tick.Lateness - time.Duration(tick.Delta-1)*interval)
+				latency := receivedAt.Sub(oldest)
+				total += latency
+				greatest = max(greatest, latency)
+
+				work := interval / 2
+				if i%2 == 0 {
+					work = interval * 12 / 10
+				}
+				for deadline := receivedAt.Add(work); hrtime.MonotonicNow() < deadline; {
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(total.Nanoseconds())/float64(b.N), "ns-mean-latency")
+			b.ReportMetric(float64(greatest.Nanoseconds()), "ns-max-latency")
+		})
+	}
+}
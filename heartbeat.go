@@ -0,0 +1,79 @@
+package hrtime
+
+import "time"
+
+// WithHeartbeat gives the ticker a second, slower channel, returned by
+// Heartbeats(), that receives a value about every every ticks, driven by
+// the same timer as the ticker's channel.  Each value is the number of
+// ticks since the previous value sent on the heartbeat channel (or since
+// Start()), whether or not those ticks were delivered on the ticker's
+// channel, so the heartbeats summarize the fast ticks without depending on
+// the fast consumer.
+//
+// A heartbeat is due when the count of ticks since Start() reaches a multiple
+// of every.  The timer can report several ticks at once, so the count at
+// which it is sent can overshoot that multiple, and the value then covers
+// more than every ticks (and the next one fewer).  Sends on the heartbeat
+// channel do not block: if no receiver is ready, the heartbeat is skipped,
+// and its ticks are added to the next heartbeat.  The heartbeat channel is
+// created by Start() and closed when the ticker stops, like the ticker's
+// channel.  An every of 0 disables it.
+func WithHeartbeat(every uint64) Option {
+	return func(config *tickerConfig) {
+		config.heartbeatEvery = every
+	}
+}
+
+// NewHeartbeatTicker creates a MonotonicTicker that ticks at the provided
+// interval on its channel, and that sends the number of ticks since the
+// previous heartbeat on the channel returned by Heartbeats() about every
+// every ticks.  It is the same as NewMonotonicTicker with the WithHeartbeat
+// option.
+func NewHeartbeatTicker(interval time.Duration, every uint64, opts ...Option) *MonotonicTicker {
+	return NewMonotonicTicker(interval, withOptions(opts, WithHeartbeat(every))...)
+}
+
+// Heartbeats returns the heartbeat channel of the ticker's current run (see
+// WithHeartbeat).  Like the ticker's channel, it is replaced by each Start(),
+// so it should be fetched again after a restart.  It returns nil if the
+// ticker has no heartbeat, or has never been started.
+func (ticker *Ticker[T]) Heartbeats() <-chan uint64 {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.handles == nil {
+		return nil
+	}
+
+	return ticker.handles.heartbeats
+}
+
+// sendHeartbeat is called by the read loop after each read of the timer.  If
+// a heartbeat is due, it attempts to send one.
+func (ticker *Ticker[T]) sendHeartbeat(handles *tickerHandles[T], tick *tickInfo) {
+	every := ticker.config.heartbeatEvery
+	if every == 0 || tick.cumulative < tick.nextHeartbeat {
+		return
+	}
+
+	if handles.trySendHeartbeat(tick.cumulative - tick.lastHeartbeat) {
+		tick.lastHeartbeat = tick.cumulative
+	}
+	tick.nextHeartbeat = (tick.cumulative/every + 1) * every
+}
+
+func (c *tickerHandles[T]) trySendHeartbeat(ticks uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.areClosed {
+		return false
+	}
+
+	select {
+	case c.heartbeats <- ticks:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestHeartbeatTicker(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewHeartbeatTicker(time.Millisecond, 3, hrtime.WithBackend(backend))
+
+	if heartbeats := ticker.Heartbeats(); heartbeats != nil {
+		t.Errorf("expected no heartbeat channel before Start()")
+	}
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	// Nothing receives from the fast channel, so the heartbeats count ticks
+	// that were never delivered.
+	timer := backend.LastTimer()
+	heartbeats := ticker.Heartbeats()
+	fired, sum := uint64(0), uint64(0)
+	for sum < 30 {
+		timer.Fire()
+		fired++
+
+		select {
+		case value := <-heartbeats:
+			sum += value
+			if value == 0 || value%3 != 0 || sum%3 != 0 || sum > fired {
+				t.Fatalf("expected each heartbeat to cover the multiple of 3 ticks since the last, got %d (%d in all, of %d ticks)", value, sum, fired)
+			}
+		case <-time.After(2 * time.Millisecond):
+		}
+	}
+	ticker.Stop()
+
+	if _, isOpen := <-heartbeats; isOpen {
+		t.Errorf("expected the heartbeat channel to be closed by Stop()")
+	}
+	if stats := ticker.Stats(); stats.Deliveries != 0 {
+		t.Errorf("expected no deliveries on the fast channel, got %d", stats.Deliveries)
+	}
+}
+
+func TestHeartbeatCoversCoalescedTicks(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewHeartbeatTicker(time.Millisecond, 10, hrtime.WithBackend(backend))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	heartbeats := ticker.Heartbeats()
+	for {
+		timer.Advance(25)
+		select {
+		case value := <-heartbeats:
+			if value%25 != 0 {
+				t.Errorf("expected a heartbeat covering whole reads of 25 ticks, got %d", value)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -3,11 +3,12 @@ package hrtime
 // hrtime aims to provide timer functions with higher resolution than the standard golang time library.
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"sync"
+	"sync/atomic"
 	"time"
-	"unsafe"
 
 	"golang.org/x/sys/unix"
 )
@@ -21,54 +22,367 @@ import (
 // tied to a tickerHandles struct with a synchronized closer.  When Start()
 // creates a new channel and a new filehandle, a new tickerHandles object is
 // also created.  Meanwhile, the previously running goroutine will hold a reference
-// to the previous set of handles.
-type tickerHandles struct {
-	tickFile      *os.File
-	sharedChannel chan uint64
+// to the previous set of handles.  Start() waits (with the ticker lock released)
+// for the previous goroutine to signal loopFinished, so that the goroutines of
+// two runs never overlap.  As each run also has its own channel, which its
+// read goroutine sends on through its own handles, no value of one run is
+// ever sent on the channel of another: a consumer that has moved to the
+// channel of a new run only receives the ticks of that run.
+//
+// Once a file descriptor is closed, the kernel can give its number to the
+// next file opened, by this or any other ticker, so a stale number would
+// read or re-arm the wrong file.  The handles therefore never keep a timer's
+// fd as a number: the timer is only used through its BackendTimer (for
+// timerfd, an os.File, which fails with os.ErrClosed once it is closed), and
+// the number is only taken inside a SyscallConn().Control() call.  The one
+// fd that is kept as a number, tickNotifier, is only used and closed with
+// mu held, and is not closed while SelectReady() uses it.
+type tickerHandles[T any] struct {
+	timer         BackendTimer
+	clock         ClockID
+	sharedChannel chan T
+	heartbeats    chan uint64
+	errors        chan error
 	mu            sync.Mutex
 	areClosed     bool
+	stopReason    error
+	lastTick      atomic.Int64
+	lastSentAt    atomic.Int64
+	lastCoalesced atomic.Bool
+	schedule      tickSchedule
+	handlerTiming handlerTiming
+	stats         tickStats
+
+	// run numbers the runs of the ticker, from 1 for the first Start().
+	run uint64
+
+	// recentIntervals is reported by RecentIntervals(), and is protected by
+	// mu.
+	recentIntervals durationRing
+
+	// deliveredCount is reported by DeliveredCount(), and, unlike stats, is
+	// never reset during a run.
+	deliveredCount atomic.Uint64
+
+	// handlerCalls queues calls for the handler pool, if there is one.
+	handlerCalls chan uint64
+
+	// dividedTickers are the tickers derived from the run by Divide(), and
+	// are protected by mu.
+	dividedTickers []*DividedTicker
+
+	// externalReads carries the counts reported by NotifyRead(), if the run
+	// is externally driven (see WithExternalDriven).
+	externalReads chan uint64
+
+	// sender is shared with the sender goroutine, if the run has one (see
+	// WithSeparateSender).
+	sender *tickSender
+
+	// overrunAlarm and adaptiveDelivery, if they are configured, are only
+	// used by the read goroutine.
+	overrunAlarm     *overrunAlarm
+	adaptiveDelivery *adaptiveDelivery
+
+	// blockingSendOn is the channel on which sendBlocking is waiting, if it
+	// is, and closeAfterBlockingSend is set if closeChannel has left that
+	// channel for it to close.  channelChanged is closed, and replaced,
+	// whenever closeChannel closes a channel.
+	blockingSendOn         chan T
+	closeAfterBlockingSend bool
+	channelChanged         chan struct{}
+
+	// tickNotifier is the eventfd used by SelectReady(), or -1 until it is
+	// first needed.  tickNotifierWaiting is set while tickNotifierUsers is
+	// not 0, so that the read goroutine can check it without the lock.
+	tickNotifier        int
+	tickNotifierUsers   int
+	tickNotifierWaiting atomic.Bool
+
+	// durationTimer, if the ticker has a duration, ends the run.
+	durationTimer *time.Timer
+
+	// ctx is passed to the handler, and is cancelled by close().
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+
+	// loopStarted is closed by the read goroutine just before its first
+	// read of the timer, loopFinished once it will deliver no more ticks
+	// (see finishLoop), and loopExited as it returns.  closed is closed by
+	// close().  loopState is reported by LoopState().
+	loopStarted    chan struct{}
+	loopFinished   chan struct{}
+	loopExited     chan struct{}
+	finishLoopOnce sync.Once
+	loopState      atomic.Int32
+	closed         chan struct{}
 }
 
-func (c *tickerHandles) close() {
+// A tickSchedule tracks when ticks should nominally occur, in the ticker's
+// clock domain, so that the lateness of each tick can be measured.  It is
+// reset each time the timer is armed, and is protected by the handles lock.
+type tickSchedule struct {
+	firstDeadline       time.Duration
+	interval            time.Duration
+	expirationsSinceArm uint64
+
+	// For a rate-scheduled ticker (see WithRate), the time at which the
+	// timer was armed and the rate, in micro-ticks per second.
+	epoch time.Duration
+	rate  uint64
+}
+
+func (c *tickerHandles[T]) close() {
+	c.closeWithReason(nil)
+}
+
+// closeWithReason closes the handles, as close() does, recording why the run
+// stopped for the OnStop() callback, if they were not already closed.
+func (c *tickerHandles[T]) closeWithReason(reason error) {
+	c.closeWith(reason, false)
+}
+
+// closeWith closes the handles as closeWithReason does.  If sentinel is true,
+// it first attempts to send the zero value of T on the channel (see
+// WithStopSentinel); as the send and the closure happen under one hold of the
+// lock, no tick can be sent between them.
+func (c *tickerHandles[T]) closeWith(reason error, sentinel bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if !c.areClosed {
-		close(c.sharedChannel)
-		c.tickFile.Close()
+		if sentinel {
+			var zero T
+			select {
+			case c.sharedChannel <- zero:
+			default:
+			}
+		}
+
+		c.stopReason = reason
+		c.closeChannel(c.sharedChannel)
+		if c.heartbeats != nil {
+			close(c.heartbeats)
+		}
+		for _, divided := range c.dividedTickers {
+			divided.stop()
+		}
+		c.dividedTickers = nil
+		close(c.errors)
+		c.timer.Close()
 		c.areClosed = true
+		close(c.closed)
+		if c.durationTimer != nil {
+			c.durationTimer.Stop()
+		}
+		c.cancelCtx()
+
+		// Wake any SelectReady(), which closes the notifier when it is the
+		// last user.
+		c.notifyTick(true)
+		if c.tickNotifier >= 0 && c.tickNotifierUsers == 0 {
+			unix.Close(c.tickNotifier)
+			c.tickNotifier = -1
+		}
 	}
 }
 
-// A MonotonicTicker is a ticker using a monotonic clock.
-type MonotonicTicker struct {
+// currentTimer returns the run's timer and its clock, which ResetClock() can
+// replace while the read goroutine is blocked reading the previous timer.
+func (c *tickerHandles[T]) currentTimer() (BackendTimer, ClockID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timer, c.clock
+}
+
+// recordDelivery records the outcome of an attempt to deliver tick.
+func (c *tickerHandles[T]) recordDelivery(tick *tickInfo, delivered bool) {
+	if delivered {
+		c.deliveredCount.Add(1)
+	}
+	if tick.warmingUp {
+		return
+	}
+
+	c.stats.recordDelivery(delivered)
+	if c.overrunAlarm != nil {
+		c.overrunAlarm.record(tick.firedAt, !delivered)
+	}
+}
+
+// readPending reads the expirations that the read goroutine has not read, if
+// the timer is a PendingReader.
+func (c *tickerHandles[T]) readPending() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if reader, isPendingReader := c.timer.(PendingReader); isPendingReader && !c.areClosed {
+		return reader.ReadPending()
+	}
+
+	return 0, nil
+}
+
+// isClosed reports whether close() has been called.
+func (c *tickerHandles[T]) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.areClosed
+}
+
+// finishLoop is called by the read goroutine when it will deliver no more
+// ticks: when it exits, or (see complete) just before it calls the onDone of
+// a countdown, which may start the ticker again.
+func (c *tickerHandles[T]) finishLoop() {
+	c.finishLoopOnce.Do(func() {
+		c.loopState.Store(int32(LoopIdle))
+		close(c.loopFinished)
+	})
+}
+
+// A Ticker is a ticker that delivers values of type T, each describing the
+// ticks that have occurred since the previous delivery.
+type Ticker[T any] struct {
 	// After the ticker is started (using Start()), periodic
-	// writes will occur on this channel.  The value is the
-	// approximate number of ticks that have occurred since the
-	// last channel read.  However, while the channel blocks
-	// for the receiver, it does not block from the ticker, so
-	// if a read is missed, one or more ticks may be lost.
-	C               chan uint64
+	// writes will occur on this channel.  The value describes
+	// the ticks that have occurred since the last channel
+	// read.  However, while the channel blocks for the
+	// receiver, it does not block from the ticker, so if a
+	// read is missed, one or more ticks will be reported
+	// together in the next value.  Several goroutines may
+	// receive from the channel at once; each value is
+	// received by exactly one of them (see WorkerPool()).
+	C               chan T
 	desiredInterval time.Duration
 	mu              sync.Mutex
-	handles         *tickerHandles
+	handles         *tickerHandles[T]
 	inStoppedState  bool
+	inPausedState   bool
+	startedAt       time.Time
+	config          tickerConfig
+	makeTick        func(tick tickInfo) T
+	rate            uint64
+	onStop          func(reason error)
+
+	// ring, if the ticker uses WithRingBuffer, receives its ticks in place
+	// of its channel.
+	ring *tickRing[T]
+
+	// deliveryMode is the DeliveryMode, which the read goroutine loads for
+	// each send.
+	deliveryMode atomic.Int32
+
+	boostTimer           *time.Timer
+	boostGeneration      uint64
+	boostRestoreInterval time.Duration
+	boostRestoreRate     uint64
+
+	// runs is the number of runs that have started.
+	runs uint64
+}
+
+// A MonotonicTicker is a ticker using a monotonic clock.  The value
+// delivered on C is the approximate number of ticks that have occurred
+// since the last channel read, which is always at least 1 (except for the
+// sentinel of WithStopSentinel).
+type MonotonicTicker = Ticker[uint64]
+
+// tickInfo describes a delivery.  It is provided by the read loop to the
+// function that creates the ticker's channel values.
+type tickInfo struct {
+	delta      uint64
+	cumulative uint64
+	sequence   uint64
+	firedAt    MonotonicTime
+	lateness   time.Duration
+
+	// The cumulative count at the last heartbeat sent, and the count at
+	// which the next is due (see WithHeartbeat).
+	lastHeartbeat uint64
+	nextHeartbeat uint64
+
+	// warmingUp is true while the ticks read are among the warm-up ticks
+	// excluded from statistics (see WithWarmupTicks).
+	warmingUp bool
+
+	// run is the run of the ticker that the delivery belongs to.
+	run uint64
 }
 
 // NewMonotonicTicker creates a ticker that is intended to fire a tick
-// near every interval.
-func NewMonotonicTicker(interval time.Duration) *MonotonicTicker {
-	return &MonotonicTicker{
+// near every interval.  By default, the ticker uses CLOCK_MONOTONIC (or the
+// clock set by SetDefaultClock); this can be changed with the WithClock
+// option.
+func NewMonotonicTicker(interval time.Duration, opts ...Option) *MonotonicTicker {
+	return newTicker(interval, opts, func(tick tickInfo) uint64 {
+		return tick.delta
+	})
+}
+
+// NewTicker creates a ticker that is intended to fire a tick near every
+// interval, and that delivers the values produced by makeTick.  makeTick is
+// called from the ticker's read goroutine each time a value is to be sent
+// on the channel, with the number of ticks since the last channel read and
+// the time at which the most recent tick was observed.  It should be cheap
+// and must not block.  NewMonotonicTicker is equivalent to NewTicker with a
+// makeTick that returns expirations.
+func NewTicker[T any](interval time.Duration, makeTick func(expirations uint64, firedAt MonotonicTime) T, opts ...Option) *Ticker[T] {
+	return newTicker(interval, opts, func(tick tickInfo) T {
+		return makeTick(tick.delta, tick.firedAt)
+	})
+}
+
+func newTicker[T any](interval time.Duration, opts []Option, makeTick func(tickInfo) T) *Ticker[T] {
+	config := newTickerConfig(opts)
+	if config.rate != 0 {
+		interval = IntervalForRate(float64(config.rate) / microTicksPerTick)
+	}
+
+	ticker := &Ticker[T]{
 		desiredInterval: interval,
 		inStoppedState:  true,
+		config:          config,
+		makeTick:        makeTick,
+		rate:            config.rate,
+		ring:            newTickRing[T](config.ringSize),
 	}
+	ticker.deliveryMode.Store(int32(config.deliveryMode))
+
+	return ticker
 }
 
 // Start starts the ticker.  Writes to ticker.C should now occur according
 // to the interval configured in the constructor.  Each time Start() is run,
 // the ticker.C channel is replaced with a new one.  Once a ticker is started,
-// Start() cannot be run again until Stop() is run on the ticker.
-func (ticker *MonotonicTicker) Start() error {
+// Start() cannot be run again until Stop() is run on the ticker.  If the read
+// goroutine of the previous run has not yet noticed Stop(), Start() waits for
+// it to exit, so no goroutine outlives its run and the previous run delivers
+// nothing once the new one has started.  Because of this, Start() must not be
+// called from the ticker's own handler or callbacks, except for the onDone of
+// WithCountdown.
+func (ticker *Ticker[T]) Start() error {
+	return ticker.start(false)
+}
+
+// StartPaused is like Start(), but leaves the ticker in the paused state:
+// the channel and read goroutine are created, but the timer is not armed, so
+// no ticks occur until Resume() is called.  This allows consumers to be set
+// up on ticker.C before the first tick can happen.
+func (ticker *Ticker[T]) StartPaused() error {
+	return ticker.start(true)
+}
+
+func (ticker *Ticker[T]) start(paused bool) (err error) {
+	if ticker.config.onCPUQuotaRisk != nil {
+		defer func() {
+			if err == nil {
+				ticker.checkCPUQuota()
+			}
+		}()
+	}
+
 	ticker.mu.Lock()
 	defer ticker.mu.Unlock()
 
@@ -76,87 +390,529 @@ func (ticker *MonotonicTicker) Start() error {
 		return fmt.Errorf("must Stop() before performing Start() again")
 	}
 
-	fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, unix.TFD_NONBLOCK|unix.TFD_CLOEXEC)
+	if previous := ticker.handles; previous != nil {
+		ticker.mu.Unlock()
+		<-previous.loopFinished
+		ticker.mu.Lock()
+
+		if !ticker.inStoppedState {
+			return fmt.Errorf("ticker was started concurrently")
+		}
+	}
+
+	base := ticker.config.baseContext
+	if base == nil {
+		base = context.Background()
+	} else if err := base.Err(); err != nil {
+		return err
+	}
+
+	timer, err := ticker.config.backend.NewTimer(ticker.config.clock)
 	if err != nil {
 		return err
 	}
 
-	timerFile := os.NewFile(uintptr(fd), "timerfd")
+	handles := &tickerHandles[T]{
+		timer:            timer,
+		clock:            ticker.config.clock,
+		sharedChannel:    make(chan T),
+		run:              ticker.runs + 1,
+		recentIntervals:  newDurationRing(ticker.config.recentIntervals),
+		errors:           make(chan error, errorsBufferLength),
+		loopStarted:      make(chan struct{}),
+		loopFinished:     make(chan struct{}),
+		loopExited:       make(chan struct{}),
+		closed:           make(chan struct{}),
+		overrunAlarm:     newOverrunAlarm(&ticker.config),
+		adaptiveDelivery: newAdaptiveDelivery(&ticker.config),
+		sender:           ticker.newTickSender(),
+		channelChanged:   make(chan struct{}),
+		tickNotifier:     -1,
+	}
+	handles.ctx, handles.cancelCtx = context.WithCancel(base)
+	if ticker.config.heartbeatEvery > 0 {
+		handles.heartbeats = make(chan uint64)
+	}
+	if ticker.config.externalDriven {
+		handles.externalReads = make(chan uint64)
+	}
 
-	itimerSpec := &unix.ItimerSpec{
-		Value:    unix.NsecToTimespec(ticker.desiredInterval.Nanoseconds()),
-		Interval: unix.NsecToTimespec(ticker.desiredInterval.Nanoseconds()),
+	if !paused {
+		if err := ticker.arm(handles, ticker.config.immediateFirstTick, ticker.config.startupJitter()); err != nil {
+			timer.Close()
+			handles.cancelCtx()
+			return err
+		}
 	}
 
-	if err := settimeUsingFile(timerFile, itimerSpec); err != nil {
-		timerFile.Close()
+	pausedAll, err := ticker.registerRun(handles, paused)
+	if err != nil {
+		timer.Close()
+		handles.cancelCtx()
 		return err
 	}
+	paused = paused || pausedAll
+
+	ticker.C = handles.sharedChannel
+	ticker.handles = handles
+	ticker.runs = handles.run
+	ticker.inStoppedState = false
+	ticker.inPausedState = paused
+	ticker.startedAt = time.Now()
+
+	ticker.startDuration(handles)
+	if ticker.config.baseContext != nil {
+		context.AfterFunc(handles.ctx, func() {
+			ticker.endRun(handles, context.Cause(handles.ctx))
+		})
+	}
+	ticker.startHandlerPool(handles)
+	if handles.sender != nil {
+		go ticker.sendLoop(handles)
+	}
+	go ticker.readLoop(ticker.handles)
+
+	return nil
+}
+
+// periodicItimerSpec returns the ItimerSpec for a timer that first expires
+// one interval from now, and every interval thereafter.
+func periodicItimerSpec(interval time.Duration) *unix.ItimerSpec {
+	return &unix.ItimerSpec{
+		Value:    unix.NsecToTimespec(interval.Nanoseconds()),
+		Interval: unix.NsecToTimespec(interval.Nanoseconds()),
+	}
+}
 
-	ticker.C = make(chan uint64)
+// arm arms the timer of handles to fire periodically at the ticker's interval,
+// starting one interval from now (or, if immediate is true, right away) plus
+// delay, and resets the nominal tick schedule to match.
+func (ticker *Ticker[T]) arm(handles *tickerHandles[T], immediate bool, delay time.Duration) error {
+	armedAt, err := handles.timer.Now()
+	if err != nil {
+		return err
+	}
+	handles.lastSentAt.Store(int64(ticker.config.timestamps.Now()))
 
-	ticker.handles = &tickerHandles{
-		tickFile:      timerFile,
-		sharedChannel: ticker.C,
+	spec, flags, schedule := ticker.arming(handles.clock, armedAt, immediate, delay)
+	if err := handles.timer.Settime(flags, spec); err != nil {
+		return err
 	}
 
-	go monotonicTickerReadLoop(ticker.handles)
+	handles.mu.Lock()
+	handles.schedule = schedule
+	handles.mu.Unlock()
 
 	return nil
 }
 
-func monotonicTickerReadLoop(handles *tickerHandles) {
-	b := make([]byte, 8)
+// arming returns the spec and flags with which arm arms a timer on clock at
+// the time armedAt on that clock, and the nominal tick schedule that follows
+// from them.  A rate-scheduled ticker (see WithRate) is armed for the
+// absolute time of its first tick.
+func (ticker *Ticker[T]) arming(clock ClockID, armedAt time.Duration, immediate bool, delay time.Duration) (*unix.ItimerSpec, int, tickSchedule) {
+	if ticker.rate != 0 {
+		schedule := ticker.rateSchedule(armedAt+scaled(delay), immediate)
+		return oneShotItimerSpec(schedule.firstDeadline), ticker.config.absoluteArmFlags(clock), schedule
+	}
+
+	interval := scaled(ticker.desiredInterval)
+	firstAfter := interval
+	if immediate {
+		firstAfter = immediateTickDelay
+	}
+	firstAfter = addSaturating(firstAfter, scaled(delay))
+
+	spec := periodicItimerSpec(interval)
+	spec.Value = unix.NsecToTimespec(int64(firstAfter))
+
+	return spec, 0, tickSchedule{
+		firstDeadline: addSaturating(armedAt, firstAfter),
+		interval:      interval,
+	}
+}
+
+// observe records that expirations ticks were just read from the timer, and
+// returns how late the most recent of them was relative to its nominal time,
+// and that nominal time, on the timer's clock.  firedAt is the time at which
+// they were read, according to the ticker's TimestampSource.
+func (ticker *Ticker[T]) observe(handles *tickerHandles[T], expirations uint64, firedAt MonotonicTime) (lateness time.Duration, nominal time.Duration) {
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	now := time.Duration(firedAt)
+	if _, isTimerfd := ticker.config.backend.(timerfdBackend); handles.clock != ClockMonotonic || !isTimerfd {
+		now, _ = handles.timer.Now()
+	}
+
+	handles.recentIntervals.record(firedAt)
+
+	schedule := &handles.schedule
+	schedule.expirationsSinceArm += expirations
+	nominal = schedule.deadline(schedule.expirationsSinceArm)
+
+	return now - nominal, nominal
+}
 
-	ticksSinceLastChannelRead := uint64(0)
+func (ticker *Ticker[T]) readLoop(handles *tickerHandles[T]) {
+	tick := tickInfo{sequence: 1, nextHeartbeat: ticker.config.heartbeatEvery, run: handles.run}
+	var lastDeliveryAttempt MonotonicTime
+	_, clock := handles.currentTimer()
+	steps := newStepDetector(&ticker.config, clock)
+	suspends := newSuspendDetector(&ticker.config)
+	defer close(handles.loopExited)
+	defer handles.unregisterRun()
+	defer ticker.runOnStop(handles)
+	defer handles.finishLoop()
+	if handles.sender != nil {
+		defer func() { <-handles.sender.exited }()
+	}
+	if handles.handlerCalls != nil {
+		defer close(handles.handlerCalls)
+	}
+	close(handles.loopStarted)
 	for {
-		bytesRead, err := handles.tickFile.Read(b)
-		if bytesRead != 8 || err != nil {
-			handles.close()
+		timer, _ := handles.currentTimer()
+		handles.loopState.Store(int32(LoopReading))
+		expirations, err := handles.readTimer(timer)
+		handles.loopState.Store(int32(LoopProcessing))
+		if errors.Is(err, unix.EINTR) || errors.Is(err, unix.EAGAIN) {
+			// os.File already retries reads that are interrupted by a
+			// signal, and the timerfd backend waits out EAGAIN itself, so
+			// this is not expected, but a Backend may return either.
+			// Neither consumes anything (the kernel only resets a
+			// timerfd's expiration count on a successful read), so
+			// retrying cannot lose ticks.
+			continue
+		}
+		if errors.Is(err, unix.ECANCELED) && steps != nil {
+			ticker.clockWasSet(handles, steps)
+			continue
+		}
+		if err != nil {
+			if current, clock := handles.currentTimer(); current != timer {
+				// ResetClock() closed the timer to replace it.
+				steps = newStepDetector(&ticker.config, clock)
+				continue
+			}
+			handles.closeWithReason(fmt.Errorf("on reading the timer: %w", err))
 			return
 		}
+		var wokeAt time.Duration
+		if ticker.config.wakeupLatency {
+			wokeAt, _ = timer.Now()
+		}
+		if ticker.config.onRawRead != nil {
+			ticker.config.onRawRead(expirations)
+		}
+
+		missed, rearmed := ticker.rearmRate(handles, expirations)
+		expirations += missed
+
+		tick.firedAt = ticker.config.timestamps.Now()
+		handles.lastTick.Store(int64(tick.firedAt))
+		handles.notifyTick(false)
+
+		if ticker.checkOverrun(handles, expirations, tick.firedAt) {
+			continue
+		}
 
-		// read bytes are in host byte order
-		ticksSinceLastChannelRead += *(*uint64)(unsafe.Pointer(&b[0]))
+		if steps != nil {
+			if step, stepped := steps.check(); stepped {
+				ticker.config.onStep(step)
+			}
+		}
+		ticker.config.checkSuspend(suspends)
+
+		var nominal time.Duration
+		tick.lateness, nominal = ticker.observe(handles, expirations, tick.firedAt)
+		tick.warmingUp = tick.cumulative < ticker.config.warmupTicks
+		if !tick.warmingUp {
+			handles.stats.recordRead(expirations, tick.lateness, rearmed)
+			if ticker.config.wakeupLatency {
+				handles.stats.recordWakeupLatency(wokeAt - nominal)
+			}
+		}
+
+		expirations = ticker.countdownExpirations(&tick, expirations)
+		tick.delta += expirations
+		tick.cumulative += expirations
+		ticker.sendHeartbeat(handles, &tick)
+		handles.countDivided(expirations)
+
+		if ticker.deliveryAllowed(&tick) && ticker.deliveryDue(&lastDeliveryAttempt, &tick) {
+			handles.loopState.Store(int32(LoopSending))
+			ticker.deliver(handles, &tick)
+		}
+		if ticker.consumerIdle(handles, tick.firedAt) {
+			continue
+		}
+
+		if ticker.countdownComplete(&tick) {
+			ticker.complete(handles)
+			return
+		}
+	}
+}
+
+// deliver hands the accumulated ticks to the coalesce handler, if there are
+// too many (see WithCoalesceHandler), or to the tick handler (or its pool)
+// or, if there is none, attempts to send them on the channel.  Once the ticks have been
+// delivered, tick.delta is reset and tick.sequence is advanced.
+func (ticker *Ticker[T]) deliver(handles *tickerHandles[T], tick *tickInfo) {
+	if ticker.coalesced(handles, tick) {
+		return
+	}
 
-		select {
-		case handles.sharedChannel <- ticksSinceLastChannelRead:
-			ticksSinceLastChannelRead = 0
+	if ticker.config.handler != nil {
+		if handles.isClosed() {
+			return
+		}
+
+		delivered := true
+		switch {
+		case handles.handlerCalls != nil:
+			delivered = ticker.dispatchHandler(handles, tick.delta)
+		case ticker.completionPaced(handles):
+			startedAt := ticker.config.timestamps.Now()
+			ticker.runHandler(handles, tick.delta)
+			ticker.pace(handles, ticker.config.timestamps.Now().Sub(startedAt))
 		default:
+			ticker.runHandler(handles, tick.delta)
+		}
+		handles.recordDelivery(tick, delivered)
+
+		if delivered {
+			handles.lastCoalesced.Store(tick.delta > 1)
+			tick.delta = 0
+			tick.sequence++
+		} else if ticker.config.skipMissed {
+			tick.delta = 0
+		}
+		return
+	}
+
+	if handles.sender != nil {
+		ticker.handToSender(handles, tick)
+		return
+	}
+
+	var sent, waited bool
+	switch {
+	case ticker.ring != nil:
+		sent = handles.pushRing(ticker.ring, ticker.makeTick(*tick))
+	case ticker.DeliveryMode() == BlockingDelivery:
+		sent, waited = handles.sendBlocking(ticker.makeTick(*tick), 0)
+	case ticker.config.handoffWait > 0:
+		sent, waited = handles.sendBlocking(ticker.makeTick(*tick), ticker.config.handoffWait)
+	default:
+		sent = handles.trySend(ticker.makeTick(*tick))
+	}
+	handles.recordDelivery(tick, sent)
+	if ticker.ring == nil {
+		ticker.adaptDelivery(handles, tick.firedAt, !sent || waited)
+	}
+
+	if sent {
+		handles.lastSentAt.Store(int64(tick.firedAt))
+		handles.lastCoalesced.Store(tick.delta > 1)
+		tick.delta = 0
+		tick.sequence++
+	} else if ticker.config.skipMissed {
+		tick.delta = 0
+	} else if threshold := ticker.config.overrunPauseThreshold; threshold > 0 && tick.delta > threshold {
+		if ticker.pauseRun(handles) && ticker.config.onOverrunPause != nil {
+			ticker.config.onOverrunPause(tick.delta)
 		}
 	}
 }
 
+// LastTickAt returns the time, according to the ticker's TimestampSource, at
+// which the read goroutine most recently observed a tick.  It returns zero if
+// the ticker has never been started, or has not yet ticked since the last
+// Start().
+func (ticker *Ticker[T]) LastTickAt() MonotonicTime {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return 0
+	}
+
+	return MonotonicTime(handles.lastTick.Load())
+}
+
+// MissedSinceLastRead reports whether the most recent delivery (a value sent
+// on the channel, or a handler call) carried more than one tick, which means
+// that the consumer, or the read goroutine, fell behind the timer.  It
+// returns false if the ticker has never been started, or has not yet
+// delivered a tick since the last Start().
+func (ticker *Ticker[T]) MissedSinceLastRead() bool {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return false
+	}
+
+	return handles.lastCoalesced.Load()
+}
+
+// trySend performs a non-blocking send of value on the current shared channel.
+// The send happens under the handles lock so that neither close() nor a
+// channel swap can race with it.  It returns false if the send did not happen,
+// either because no receiver was ready or because the handles are closed.
+func (c *tickerHandles[T]) trySend(value T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.areClosed {
+		return false
+	}
+
+	select {
+	case c.sharedChannel <- value:
+		return true
+	default:
+		return false
+	}
+}
+
+// pushRing writes value into ring, unless the handles are closed, in which
+// case it returns false.  The ring itself is lock-free; the handles lock is
+// only held long enough to check that the run has not stopped, so that a
+// stopped run writes nothing.
+func (c *tickerHandles[T]) pushRing(ring *tickRing[T], value T) bool {
+	if c.isClosed() {
+		return false
+	}
+
+	ring.push(value)
+
+	return true
+}
+
+// SwapChannel replaces the channel on which a running ticker delivers ticks
+// with a new one, and sets ticker.C to the new channel.  The old channel is
+// closed as part of the swap, so a consumer may drain it until it observes
+// the closure and then move to the new channel.  Ticks that accumulate while
+// no receiver is ready are carried over, so every tick is delivered on exactly
+// one of the two channels.  If the ticker is not running, SwapChannel does
+// nothing and returns the current channel as both oldC and newC.
+func (ticker *Ticker[T]) SwapChannel() (oldC <-chan T, newC <-chan T) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return ticker.C, ticker.C
+	}
+
+	handles := ticker.handles
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	if handles.areClosed {
+		return ticker.C, ticker.C
+	}
+
+	previous := handles.sharedChannel
+	handles.sharedChannel = make(chan T)
+	handles.closeChannel(previous)
+
+	ticker.C = handles.sharedChannel
+
+	return previous, handles.sharedChannel
+}
+
 // Stop stops a running ticker.  The associated channel will be closed
 // from the ticker side.
-func (ticker *MonotonicTicker) Stop() error {
+func (ticker *Ticker[T]) Stop() error {
+	_, err := ticker.stop(false)
+	return err
+}
+
+// StopWithResidual stops the ticker as Stop() does, and returns the number of
+// expirations that had occurred but had not yet been read by the read
+// goroutine, which it reads from the timer just before closing it.  Only
+// expirations counted by the timer are included: ticks that the read
+// goroutine had already read, but not delivered because no receiver was
+// ready, are not.  (An expiration that the read goroutine reads at the same
+// moment is delivered as usual, and not included.)  It returns 0 if the
+// ticker was already stopped, if its backend does not implement
+// PendingReader, or if the ticker is externally driven (see
+// WithExternalDriven).
+func (ticker *Ticker[T]) StopWithResidual() (uint64, error) {
+	return ticker.stop(true)
+}
+
+// WaitStopped blocks until the ticker's most recent run has completely
+// stopped: its timer is closed, its channel is closed, and its read goroutine
+// has returned, after calling the OnStop() callback.  Stop() closes the timer
+// and the channel before it returns, but the read goroutine notices only
+// afterwards, so WaitStopped is for callers (such as tests that count
+// goroutines or file descriptors) that must know that nothing of the run
+// remains.  If the ticker is running, WaitStopped waits until it is stopped,
+// by Stop() or on its own (as with WithCountdown).  It returns at once if the
+// ticker has never been started.  Workers of a handler pool (see
+// WithHandlerPool) may still be finishing their queued calls when it returns.
+func (ticker *Ticker[T]) WaitStopped() {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles != nil {
+		<-handles.loopExited
+	}
+}
+
+func (ticker *Ticker[T]) stop(readResidual bool) (uint64, error) {
 	ticker.mu.Lock()
 	handles := ticker.handles
+	wasStopped := ticker.inStoppedState
 	ticker.inStoppedState = true
+	ticker.startedAt = time.Time{}
+	ticker.cancelBoost()
 	ticker.mu.Unlock()
 
-	handles.close()
+	// A ticker that stopped itself (see WithCountdown) closes its own
+	// handles.
+	if handles == nil || wasStopped {
+		return 0, nil
+	}
 
-	return nil
+	var residual uint64
+	var err error
+	if readResidual && !ticker.config.externalDriven {
+		residual, err = handles.readPending()
+	}
+	handles.closeWith(nil, ticker.config.stopSentinel && ticker.config.handler == nil && ticker.ring == nil)
+
+	return residual, err
 }
 
-func settimeUsingFile(f *os.File, itimerSpec *unix.ItimerSpec) error {
-	raw, err := f.SyscallConn()
-	if err != nil {
-		return err
-	}
+// RunningSince returns the time at which the ticker was last started.  The
+// returned time carries a monotonic clock reading, so it is safe to subtract
+// from other times returned by time.Now().  It returns the zero time if the
+// ticker is stopped.
+func (ticker *Ticker[T]) RunningSince() time.Time {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
 
-	var fdSettimeError error
-	err = raw.Control(func(fdInControl uintptr) {
-		fdSettimeError = unix.TimerfdSettime(int(fdInControl), 0, itimerSpec, nil)
-	})
+	return ticker.startedAt
+}
 
-	if fdSettimeError != nil {
-		return fdSettimeError
-	}
-	if err != nil {
-		return err
+// Uptime returns how long the ticker has been running since it was last
+// started, including any time spent paused.  It returns zero if the ticker
+// is stopped.
+func (ticker *Ticker[T]) Uptime() time.Duration {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.startedAt.IsZero() {
+		return 0
 	}
 
-	return nil
+	return time.Since(ticker.startedAt)
 }
@@ -2,10 +2,17 @@ package hrtime_test
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
 )
 
 func TestMonotonicTicker(t *testing.T) {
@@ -78,3 +85,568 @@ func monotonicTicker10SecondTest(ticker *hrtime.MonotonicTicker) error {
 
 	return nil
 }
+
+func TestMonotonicTickerSwapChannel(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(10 * time.Millisecond)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if _, open := <-ticker.C; !open {
+		t.Fatalf("on read before swap, channel is closed")
+	}
+
+	oldC, newC := ticker.SwapChannel()
+	if oldC == newC {
+		t.Fatalf("expected SwapChannel() to return distinct channels")
+	}
+
+	if _, open := <-oldC; open {
+		t.Errorf("expected old channel to be closed after swap")
+	}
+
+	if ticks, open := <-newC; !open {
+		t.Errorf("on read of new channel, channel is closed")
+	} else if ticks < 1 {
+		t.Errorf("on read of new channel, expected tick count >= 1, got %d", ticks)
+	}
+}
+
+func TestMonotonicTickerUptime(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(10 * time.Millisecond)
+
+	if !ticker.RunningSince().IsZero() || ticker.Uptime() != 0 {
+		t.Errorf("expected zero RunningSince() and Uptime() before Start()")
+	}
+
+	beforeStart := time.Now()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if since := ticker.RunningSince(); since.Before(beforeStart) || since.After(time.Now()) {
+		t.Errorf("expected RunningSince() to be between %s and now, got %s", beforeStart, since)
+	}
+	if uptime := ticker.Uptime(); uptime < 20*time.Millisecond || uptime > time.Since(beforeStart) {
+		t.Errorf("expected Uptime() to be between 20ms and %s, got %s", time.Since(beforeStart), uptime)
+	}
+
+	ticker.Stop()
+
+	if !ticker.RunningSince().IsZero() || ticker.Uptime() != 0 {
+		t.Errorf("expected zero RunningSince() and Uptime() after Stop()")
+	}
+}
+
+func TestMonotonicTickerSurvivesSignals(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	ticker := hrtime.NewRichTicker(5 * time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+	hrtime.WaitForReadLoop(ticker)
+
+	stopSignalling := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stopSignalling:
+				return
+			case <-signals:
+			default:
+				syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+				time.Sleep(100 * time.Microsecond)
+			}
+		}
+	}()
+
+	startedAt := time.Now()
+	var last hrtime.RichTick
+	for time.Since(startedAt) < 200*time.Millisecond {
+		tick, open := <-ticker.C
+		if !open {
+			close(stopSignalling)
+			t.Fatalf("channel closed while signals were being delivered")
+		}
+		last = tick
+	}
+	close(stopSignalling)
+
+	if expected := uint64(time.Since(startedAt) / (5 * time.Millisecond)); last.Cumulative+2 < expected {
+		t.Errorf("expected about %d ticks while signals were being delivered, got %d", expected, last.Cumulative)
+	}
+}
+
+func TestTickerWithCustomElement(t *testing.T) {
+	type element struct {
+		expirations uint64
+		firedAt     hrtime.MonotonicTime
+	}
+
+	ticker := hrtime.NewTicker(10*time.Millisecond, func(expirations uint64, firedAt hrtime.MonotonicTime) element {
+		return element{expirations, firedAt}
+	})
+
+	startedAt := hrtime.MonotonicNow()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	e := <-ticker.C
+	if e.expirations != 1 {
+		t.Errorf("expected expirations = 1, got %d", e.expirations)
+	}
+	if elapsed := e.firedAt.Sub(startedAt); elapsed < 10*time.Millisecond || elapsed > 50*time.Millisecond {
+		t.Errorf("expected firedAt to be between 10ms and 50ms after Start(), got %s", elapsed)
+	}
+}
+
+func TestTickerWithImmediateFirstTick(t *testing.T) {
+	ticker := hrtime.NewRichTicker(time.Hour, hrtime.WithImmediateFirstTick())
+
+	startedAt := hrtime.MonotonicNow()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	select {
+	case tick := <-ticker.C:
+		if tick.Delta != 1 || tick.Cumulative != 1 || tick.Seq != 1 {
+			t.Errorf("expected immediate tick to be counted as the first tick, got %+v", tick)
+		}
+		if elapsed := tick.FiredAt.Sub(startedAt); elapsed > 50*time.Millisecond {
+			t.Errorf("expected immediate tick within 50ms of Start(), got %s", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no immediate tick after Start()")
+	}
+
+	select {
+	case tick := <-ticker.C:
+		t.Errorf("expected no second tick before the interval, got %+v", tick)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestTickerWithSkipMissed(t *testing.T) {
+	for _, skipMissed := range []bool{false, true} {
+		backend := hrtimetest.NewBackend()
+		opts := []hrtime.Option{hrtime.WithBackend(backend)}
+		if skipMissed {
+			opts = append(opts, hrtime.WithSkipMissed())
+		}
+		ticker := hrtime.NewRichTicker(time.Millisecond, opts...)
+
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+
+		// Nothing is receiving, so these ticks are missed.
+		timer := backend.LastTimer()
+		timer.Advance(5)
+		timer.WaitForReader()
+
+		received := make(chan hrtime.RichTick, 1)
+		go func() { received <- <-ticker.C }()
+
+		var tick hrtime.RichTick
+		for waiting := true; waiting; {
+			timer.Fire()
+			timer.WaitForReader()
+			select {
+			case tick = <-received:
+				waiting = false
+			default:
+			}
+		}
+		ticker.Stop()
+
+		if skipMissed && tick.Delta != 1 {
+			t.Errorf("with WithSkipMissed(), expected first received tick to have Delta = 1, got %d", tick.Delta)
+		}
+		if !skipMissed && tick.Delta != tick.Cumulative {
+			t.Errorf("by default, expected first received tick to include all %d ticks, got %d", tick.Cumulative, tick.Delta)
+		}
+		if tick.Cumulative < 6 {
+			t.Errorf("expected Cumulative to include the missed ticks, got %d", tick.Cumulative)
+		}
+	}
+}
+
+func TestTickerStopStartStress(t *testing.T) {
+	goroutinesBefore := runtime.NumGoroutine()
+
+	ticker := hrtime.NewRichTicker(20 * time.Microsecond)
+	for cycle := 0; cycle < 2000; cycle++ {
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() of cycle %d: %s", cycle, err.Error())
+		}
+
+		select {
+		case tick, open := <-ticker.C:
+			if open && tick.Seq != 1 {
+				t.Fatalf("on cycle %d, expected first value on the new channel to have Seq = 1, got %d", cycle, tick.Seq)
+			}
+		case <-time.After(time.Duration(cycle%3) * 20 * time.Microsecond):
+		}
+
+		ticker.Stop()
+	}
+
+	handlerCalls := atomic.Int32{}
+	handlerTicker := hrtime.NewMonotonicTicker(20*time.Microsecond, hrtime.WithHandler(func(uint64) {
+		if handlerCalls.Add(1) > 1 {
+			t.Errorf("handler of a stopped run was called during a later run")
+		}
+		time.Sleep(10 * time.Microsecond)
+		handlerCalls.Add(-1)
+	}))
+	for cycle := 0; cycle < 2000; cycle++ {
+		if err := handlerTicker.Start(); err != nil {
+			t.Fatalf("on Start() of handler cycle %d: %s", cycle, err.Error())
+		}
+		time.Sleep(time.Duration(cycle%3) * 20 * time.Microsecond)
+		handlerTicker.Stop()
+	}
+	handlerTicker.Start()
+	handlerTicker.Stop()
+
+	// Give the read goroutine of the final run time to exit.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutinesBefore && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if goroutines := runtime.NumGoroutine(); goroutines > goroutinesBefore {
+		t.Errorf("expected no leaked goroutines, %d remain of %d before", goroutines, goroutinesBefore)
+	}
+}
+
+func TestTickerMissedSinceLastRead(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {}))
+
+	if ticker.MissedSinceLastRead() {
+		t.Errorf("expected MissedSinceLastRead() to be false before Start()")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.Fire()
+	timer.WaitForReader()
+	if ticker.MissedSinceLastRead() {
+		t.Errorf("expected MissedSinceLastRead() to be false after a single tick")
+	}
+
+	timer.Advance(3)
+	timer.WaitForReader()
+	if !ticker.MissedSinceLastRead() {
+		t.Errorf("expected MissedSinceLastRead() to be true after 3 coalesced ticks")
+	}
+
+	timer.Fire()
+	timer.WaitForReader()
+	if ticker.MissedSinceLastRead() {
+		t.Errorf("expected MissedSinceLastRead() to be false after catching up")
+	}
+}
+
+func TestStopWithResidual(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	inHandler, release := make(chan struct{}), make(chan struct{})
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {
+		inHandler <- struct{}{}
+		<-release
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	// While the read goroutine is held in the handler, expirations
+	// accumulate in the timer.
+	timer := backend.LastTimer()
+	timer.Fire()
+	<-inHandler
+	timer.Advance(4)
+
+	residual, err := ticker.StopWithResidual()
+	close(release)
+	if err != nil {
+		t.Fatalf("on StopWithResidual(): %s", err.Error())
+	}
+	if residual != 4 {
+		t.Errorf("expected a residual of 4 expirations, got %d", residual)
+	}
+
+	if residual, err := ticker.StopWithResidual(); residual != 0 || err != nil {
+		t.Errorf("expected StopWithResidual() of a stopped ticker to return 0 and no error, got %d, %v", residual, err)
+	}
+}
+
+func TestStopWithResidualReadsTimerfd(t *testing.T) {
+	inHandler, release := make(chan struct{}, 1), make(chan struct{})
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithHandler(func(uint64) {
+		select {
+		case inHandler <- struct{}{}:
+		default:
+		}
+		<-release
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	<-inHandler
+	time.Sleep(20 * time.Millisecond)
+
+	residual, err := ticker.StopWithResidual()
+	close(release)
+	if err != nil {
+		t.Fatalf("on StopWithResidual(): %s", err.Error())
+	}
+	if residual < 10 || residual > 1000 {
+		t.Errorf("expected about 20 expirations to be pending, got %d", residual)
+	}
+}
+
+func TestTickerWithRawReadHook(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	var mu sync.Mutex
+	var reads []uint64
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithRawReadHook(func(expirations uint64) {
+		mu.Lock()
+		reads = append(reads, expirations)
+		mu.Unlock()
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// Nothing receives from the channel, so the ticks are coalesced, but the
+	// hook sees each read as the timer reported it.
+	timer := backend.LastTimer()
+	advances := []uint64{3, 1, 5}
+	for _, advance := range advances {
+		timer.Advance(advance)
+		timer.WaitForReader()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reads) != len(advances) {
+		t.Fatalf("expected %d raw reads, got %d", len(advances), len(reads))
+	}
+	for i, advance := range advances {
+		if reads[i] != advance {
+			t.Errorf("on raw read %d, expected %d expirations, got %d", i, advance, reads[i])
+		}
+	}
+}
+
+func TestTickerWaitStopped(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	ticker.WaitStopped()
+
+	openFDs := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Fatalf("on reading /proc/self/fd: %s", err.Error())
+		}
+		return len(entries)
+	}
+	fdsBefore := openFDs()
+
+	var onStopCalled atomic.Bool
+	ticker.OnStop(func(error) { onStopCalled.Store(true) })
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	<-ticker.C
+
+	ticker.Stop()
+	ticker.WaitStopped()
+
+	if !onStopCalled.Load() {
+		t.Errorf("expected WaitStopped() to return after the OnStop() callback")
+	}
+	if state := ticker.LoopState(); state != hrtime.LoopIdle {
+		t.Errorf("expected the read goroutine to be idle, got %s", state)
+	}
+	if fds := openFDs(); fds != fdsBefore {
+		t.Errorf("expected %d open fds after WaitStopped(), got %d", fdsBefore, fds)
+	}
+}
+
+func TestTickerWaitStoppedForCountdown(t *testing.T) {
+	ticker := hrtime.NewCountdownTicker(time.Millisecond, 3, nil)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	go func() {
+		for range ticker.C {
+		}
+	}()
+
+	waited := make(chan struct{})
+	go func() {
+		ticker.WaitStopped()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected WaitStopped() to return once the countdown completed")
+	}
+}
+
+func TestTickerWithStopSentinel(t *testing.T) {
+	for _, withSentinel := range []bool{false, true} {
+		var opts []hrtime.Option
+		if withSentinel {
+			opts = append(opts, hrtime.WithStopSentinel())
+		}
+		ticker := hrtime.NewMonotonicTicker(time.Hour, opts...)
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+
+		received := make(chan []uint64)
+		go func() {
+			var values []uint64
+			for value := range ticker.C {
+				values = append(values, value)
+			}
+			received <- values
+		}()
+
+		// Give the receiver time to block on the channel.
+		time.Sleep(20 * time.Millisecond)
+		ticker.Stop()
+
+		values := <-received
+		if withSentinel && (len(values) != 1 || values[0] != 0) {
+			t.Errorf("expected Stop() to send a single 0 sentinel before closing the channel, got %v", values)
+		}
+		if !withSentinel && len(values) != 0 {
+			t.Errorf("expected no values without WithStopSentinel(), got %v", values)
+		}
+	}
+}
+
+// eagainBackend creates fake timers whose first reads fail with EAGAIN, as
+// a non-blocking read with nothing to read does.
+type eagainBackend struct {
+	*hrtimetest.Backend
+	eagains int32
+}
+
+func (backend *eagainBackend) NewTimer(clock hrtime.ClockID) (hrtime.BackendTimer, error) {
+	timer, err := backend.Backend.NewTimer(clock)
+	if err != nil {
+		return nil, err
+	}
+
+	eagainTimer := &eagainTimer{Timer: timer.(*hrtimetest.Timer)}
+	eagainTimer.eagains.Store(backend.eagains)
+
+	return eagainTimer, nil
+}
+
+type eagainTimer struct {
+	*hrtimetest.Timer
+	eagains atomic.Int32
+}
+
+func (timer *eagainTimer) Read() (uint64, error) {
+	if timer.eagains.Add(-1) >= 0 {
+		return 0, syscall.EAGAIN
+	}
+
+	return timer.Timer.Read()
+}
+
+func TestTickerRetriesReadOnEAGAIN(t *testing.T) {
+	backend := &eagainBackend{Backend: hrtimetest.NewBackend(), eagains: 3}
+
+	var handled atomic.Uint64
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithHandler(func(expirations uint64) {
+		handled.Add(expirations)
+	}))
+	reasons := make(chan error, 1)
+	ticker.OnStop(func(reason error) { reasons <- reason })
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.Advance(2)
+	timer.WaitForReader()
+
+	select {
+	case reason := <-reasons:
+		t.Fatalf("expected EAGAIN not to stop the ticker, but it stopped: %v", reason)
+	default:
+	}
+	if expirations := handled.Load(); expirations != 2 {
+		t.Errorf("expected the expirations to be read after the EAGAIN retries, got %d", expirations)
+	}
+}
+
+func TestTickerRunsDoNotCrossChannels(t *testing.T) {
+	ticker := hrtime.NewRunTaggedTicker(20 * time.Microsecond)
+
+	var received atomic.Uint64
+	var consumers sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		if i%2 == 0 {
+			ticker.SetDeliveryMode(hrtime.NonBlockingDelivery)
+		} else {
+			ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+		}
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() %d: %s", i, err.Error())
+		}
+		run, c := hrtime.CurrentRun(ticker), ticker.C
+
+		// Each run's consumer keeps draining its channel after Stop(),
+		// while the next run starts.
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for value := range c {
+				if value != run {
+					t.Errorf("expected only values of run %d on its channel, got one of run %d", run, value)
+				}
+				received.Add(1)
+			}
+		}()
+
+		time.Sleep(time.Duration(i%4) * 20 * time.Microsecond)
+		ticker.Stop()
+	}
+	consumers.Wait()
+
+	if received.Load() == 0 {
+		t.Errorf("expected some values to be received")
+	}
+}
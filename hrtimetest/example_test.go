@@ -0,0 +1,37 @@
+package hrtimetest_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func Example() {
+	backend := hrtimetest.NewBackend()
+
+	ticker := hrtime.NewMonotonicTicker(time.Hour,
+		hrtime.WithBackend(backend),
+		hrtime.WithHandler(func(expirations uint64) {
+			fmt.Printf("handled %d tick(s)\n", expirations)
+		}),
+	)
+
+	if err := ticker.Start(); err != nil {
+		panic(err)
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+
+	timer.Fire()
+	timer.WaitForReader()
+
+	timer.Advance(3)
+	timer.WaitForReader()
+
+	// Output:
+	// handled 1 tick(s)
+	// handled 3 tick(s)
+}
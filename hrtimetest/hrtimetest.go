@@ -0,0 +1,317 @@
+// Package hrtimetest provides a deterministic fake hrtime.Backend, so that
+// code that consumes hrtime tickers can be tested without sleeping or
+// touching real timers.  Time only passes for a fake timer when the test
+// says so, with Advance(), Fire() or AdvanceTime().
+package hrtimetest
+
+import (
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"golang.org/x/sys/unix"
+)
+
+// A Backend is a fake hrtime.Backend.  Provide it to a ticker with
+// hrtime.WithBackend.  Each timer it creates has its own fake clock, which
+// starts at zero.
+type Backend struct {
+	mu     sync.Mutex
+	timers []*Timer
+}
+
+// NewBackend creates a fake backend.
+func NewBackend() *Backend {
+	return &Backend{}
+}
+
+// NewTimer implements hrtime.Backend.
+func (backend *Backend) NewTimer(clock hrtime.ClockID) (hrtime.BackendTimer, error) {
+	timer := &Timer{clock: clock}
+	timer.cond = sync.NewCond(&timer.mu)
+
+	backend.mu.Lock()
+	backend.timers = append(backend.timers, timer)
+	backend.mu.Unlock()
+
+	return timer, nil
+}
+
+// Timers returns every timer that the backend has created, in order of
+// creation.  A ticker creates a new timer each time it is started.
+func (backend *Backend) Timers() []*Timer {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	return append([]*Timer(nil), backend.timers...)
+}
+
+// LastTimer returns the timer that the backend created most recently, or nil
+// if it has not created one.
+func (backend *Backend) LastTimer() *Timer {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	if len(backend.timers) == 0 {
+		return nil
+	}
+
+	return backend.timers[len(backend.timers)-1]
+}
+
+// A Timer is a fake hrtime.BackendTimer.  It follows timerfd semantics:
+// expirations accumulate until they are consumed by Read(), and arming the
+// timer discards unread expirations.
+type Timer struct {
+	mu             sync.Mutex
+	cond           *sync.Cond
+	clock          hrtime.ClockID
+	now            time.Duration
+	deadline       time.Duration
+	interval       time.Duration
+	pending        uint64
+	blockedReaders int
+	closed         bool
+	lastSpec       unix.ItimerSpec
+	lastFlags      int
+	settimeError   error
+	cancelOnSet    bool
+	canceled       bool
+}
+
+// Settime implements hrtime.BackendTimer.
+func (timer *Timer) Settime(flags int, spec *unix.ItimerSpec) error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return os.ErrClosed
+	}
+
+	if err := timer.settimeError; err != nil {
+		timer.settimeError = nil
+		return err
+	}
+
+	timer.lastSpec, timer.lastFlags = *spec, flags
+	timer.pending = 0
+	timer.canceled = false
+	timer.cancelOnSet = flags&unix.TFD_TIMER_ABSTIME != 0 && flags&unix.TFD_TIMER_CANCEL_ON_SET != 0 && timer.clock == hrtime.ClockRealtime
+	timer.interval = time.Duration(spec.Interval.Nano())
+
+	switch value := time.Duration(spec.Value.Nano()); {
+	case value == 0:
+		timer.deadline = 0
+	case flags&unix.TFD_TIMER_ABSTIME != 0:
+		timer.deadline = value
+	case value > math.MaxInt64-timer.now:
+		// The kernel limits arming to the greatest representable time.
+		timer.deadline = math.MaxInt64
+	default:
+		timer.deadline = timer.now + value
+	}
+
+	timer.cond.Broadcast()
+
+	return nil
+}
+
+// Gettime implements hrtime.BackendTimer.
+func (timer *Timer) Gettime() (*unix.ItimerSpec, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return nil, os.ErrClosed
+	}
+
+	spec := &unix.ItimerSpec{Interval: unix.NsecToTimespec(int64(timer.interval))}
+	if timer.armed() {
+		spec.Value = unix.NsecToTimespec(int64(timer.deadline - timer.now))
+	}
+
+	return spec, nil
+}
+
+// Read implements hrtime.BackendTimer.  It blocks until there are unread
+// expirations or the timer is closed.
+func (timer *Timer) Read() (uint64, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	for timer.pending == 0 && !timer.closed && !timer.canceled {
+		timer.blockedReaders++
+		timer.cond.Broadcast()
+		timer.cond.Wait()
+		timer.blockedReaders--
+	}
+
+	if timer.closed {
+		return 0, os.ErrClosed
+	}
+	if timer.canceled {
+		timer.canceled = false
+		timer.pending = 0
+		return 0, unix.ECANCELED
+	}
+
+	expirations := timer.pending
+	timer.pending = 0
+
+	return expirations, nil
+}
+
+// ReadPending implements hrtime.PendingReader.  It consumes the unread
+// expirations, if there are any, without blocking.
+func (timer *Timer) ReadPending() (uint64, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return 0, os.ErrClosed
+	}
+
+	expirations := timer.pending
+	timer.pending = 0
+
+	return expirations, nil
+}
+
+// Now implements hrtime.BackendTimer.  It returns the timer's fake clock.
+func (timer *Timer) Now() (time.Duration, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	return timer.now, nil
+}
+
+// Close implements hrtime.BackendTimer.
+func (timer *Timer) Close() error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	timer.closed = true
+	timer.cond.Broadcast()
+
+	return nil
+}
+
+// Clock returns the clock that the timer was created for.
+func (timer *Timer) Clock() hrtime.ClockID {
+	return timer.clock
+}
+
+// Armed reports whether the timer is armed.
+func (timer *Timer) Armed() bool {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	return timer.armed()
+}
+
+func (timer *Timer) armed() bool {
+	return timer.deadline != 0
+}
+
+// LastSettime returns the spec and flags of the most recent call to
+// Settime().
+func (timer *Timer) LastSettime() (spec unix.ItimerSpec, flags int) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	return timer.lastSpec, timer.lastFlags
+}
+
+// FailNextSettime makes the next call to Settime() return err without
+// changing the timer, as timerfd_settime(2) does when it fails.
+func (timer *Timer) FailNextSettime(err error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	timer.settimeError = err
+}
+
+// Fire simulates one expiration of the timer.  It is the same as Advance(1).
+func (timer *Timer) Fire() {
+	timer.Advance(1)
+}
+
+// Advance simulates n expirations of an armed timer, moving its fake clock
+// forward to the n-th expiration.  A one-shot timer (one with a zero
+// interval) expires at most once, then becomes disarmed.  Advance does
+// nothing if the timer is disarmed.
+func (timer *Timer) Advance(n uint64) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	for ; n > 0 && timer.armed(); n-- {
+		timer.expire()
+	}
+
+	timer.cond.Broadcast()
+}
+
+// AdvanceTime moves the timer's fake clock forward by d, simulating every
+// expiration that falls within that time.  It returns the number of
+// expirations simulated.
+func (timer *Timer) AdvanceTime(d time.Duration) uint64 {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	target := timer.now + d
+	expirations := uint64(0)
+	for timer.armed() && timer.deadline <= target {
+		timer.expire()
+		expirations++
+	}
+	timer.now = target
+
+	timer.cond.Broadcast()
+
+	return expirations
+}
+
+// SetClock sets the timer's fake clock to now, as an operator or NTP setting
+// CLOCK_REALTIME would, without simulating any expiration: a timer armed for
+// an absolute time keeps its deadline, however far the clock moved from it.
+// As with a timerfd, if the timer is on ClockRealtime and was armed with
+// TFD_TIMER_ABSTIME|TFD_TIMER_CANCEL_ON_SET, its blocked or next Read()
+// fails with ECANCELED, and discards any unread expirations.
+func (timer *Timer) SetClock(now time.Duration) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	timer.now = now
+	if timer.cancelOnSet && timer.armed() {
+		timer.canceled = true
+	}
+
+	timer.cond.Broadcast()
+}
+
+// expire must be called with the lock held, and only on an armed timer.
+func (timer *Timer) expire() {
+	timer.now = timer.deadline
+	timer.pending++
+
+	if timer.interval == 0 {
+		timer.deadline = 0
+	} else {
+		timer.deadline += timer.interval
+	}
+}
+
+// WaitForReader blocks until a Read() is blocked waiting for expirations.
+// After simulating expirations, a test can call WaitForReader to know that
+// the ticker's read goroutine has consumed them, and has finished handling
+// them (sending on its channel, or calling its handler).
+func (timer *Timer) WaitForReader() {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	for (timer.blockedReaders == 0 || timer.pending > 0 || timer.canceled) && !timer.closed {
+		timer.cond.Wait()
+	}
+}
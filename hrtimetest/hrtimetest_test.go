@@ -0,0 +1,104 @@
+package hrtimetest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestTimerFollowsTimerfdSemantics(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	genericTimer, err := backend.NewTimer(hrtime.ClockMonotonic)
+	if err != nil {
+		t.Fatalf("on NewTimer(): %s", err.Error())
+	}
+	timer := backend.LastTimer()
+	if genericTimer != hrtime.BackendTimer(timer) {
+		t.Fatalf("expected LastTimer() to return the timer created by NewTimer()")
+	}
+
+	timer.Fire()
+	if timer.Armed() {
+		t.Errorf("expected a timer that was never armed to remain disarmed")
+	}
+
+	if err := timer.Settime(0, &unix.ItimerSpec{
+		Value:    unix.NsecToTimespec(int64(10 * time.Millisecond)),
+		Interval: unix.NsecToTimespec(int64(5 * time.Millisecond)),
+	}); err != nil {
+		t.Fatalf("on Settime(): %s", err.Error())
+	}
+
+	if expirations := timer.AdvanceTime(22 * time.Millisecond); expirations != 3 {
+		t.Errorf("expected 3 expirations in 22ms, got %d", expirations)
+	}
+
+	spec, _ := timer.Gettime()
+	if remaining := time.Duration(spec.Value.Nano()); remaining != 3*time.Millisecond {
+		t.Errorf("expected 3ms until next expiration, got %s", remaining)
+	}
+
+	timer.Advance(2)
+	if expirations, _ := timer.Read(); expirations != 5 {
+		t.Errorf("expected Read() to return 5 accumulated expirations, got %d", expirations)
+	}
+	if now, _ := timer.Now(); now != 30*time.Millisecond {
+		t.Errorf("expected fake clock at 30ms, got %s", now)
+	}
+
+	if err := timer.Settime(unix.TFD_TIMER_ABSTIME, &unix.ItimerSpec{
+		Value: unix.NsecToTimespec(int64(50 * time.Millisecond)),
+	}); err != nil {
+		t.Fatalf("on second Settime(): %s", err.Error())
+	}
+
+	timer.Advance(10)
+	if expirations, _ := timer.Read(); expirations != 1 {
+		t.Errorf("expected one-shot timer to expire once, got %d", expirations)
+	}
+	if timer.Armed() {
+		t.Errorf("expected one-shot timer to be disarmed after expiring")
+	}
+
+	timer.Close()
+	if _, err := timer.Read(); err == nil {
+		t.Errorf("expected error on Read() after Close()")
+	}
+}
+
+func TestTickerWithFakeBackend(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	var handled []uint64
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithHandler(func(expirations uint64) {
+		handled = append(handled, expirations)
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Interval.Nano()) != time.Second {
+		t.Errorf("expected ticker to arm the timer with a 1s interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+
+	advances := []uint64{1, 4, 2}
+	for _, advance := range advances {
+		timer.Advance(advance)
+		timer.WaitForReader()
+	}
+
+	if len(handled) != len(advances) {
+		t.Fatalf("expected %d handler calls, got %d", len(advances), len(handled))
+	}
+	for i, advance := range advances {
+		if handled[i] != advance {
+			t.Errorf("on handler call %d, expected %d expirations, got %d", i, advance, handled[i])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package hrtime
+
+import "time"
+
+// An InspectResult describes a ticker at one moment (see Inspect).
+type InspectResult struct {
+	// Interval is the ticker's current interval, as changed by Reset(), and
+	// Clock its current clock, as changed by ResetClock().
+	Interval time.Duration
+	Clock    ClockID
+
+	// Running is true if the ticker has been started and not stopped since,
+	// and Paused if, in addition, it is paused.
+	Running bool
+	Paused  bool
+
+	// Uptime is as Uptime() reports it.
+	Uptime time.Duration
+
+	// Delivered and Dropped are the Deliveries and Drops of Stats().  They
+	// are zero if the ticker has never been started.
+	Delivered uint64
+	Dropped   uint64
+}
+
+// Inspect returns a description of the ticker in which every field is read at
+// the same moment: the ticker's lock, which Start(), Stop(), Pause(), Reset()
+// and the like hold while they change it, is held throughout, along with the
+// lock of its statistics, so the result is consistent where separate calls
+// to Uptime(), Stats() and the like could each see a different state.
+func (ticker *Ticker[T]) Inspect() InspectResult {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	result := InspectResult{
+		Interval: ticker.desiredInterval,
+		Clock:    ticker.config.clock,
+		Running:  !ticker.inStoppedState,
+		Paused:   !ticker.inStoppedState && ticker.inPausedState,
+	}
+	if !ticker.startedAt.IsZero() {
+		result.Uptime = time.Since(ticker.startedAt)
+	}
+
+	if handles := ticker.handles; handles != nil {
+		stats := handles.stats.snapshot(false)
+		result.Delivered = stats.Deliveries
+		result.Dropped = stats.Drops
+	}
+
+	return result
+}
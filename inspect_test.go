@@ -0,0 +1,51 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestInspect(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithClock(hrtime.ClockBoottime))
+
+	if result := ticker.Inspect(); result != (hrtime.InspectResult{Interval: time.Millisecond, Clock: hrtime.ClockBoottime}) {
+		t.Errorf("expected only the interval and clock of a ticker never started, got %+v", result)
+	}
+
+	if err := ticker.StartPaused(); err != nil {
+		t.Fatalf("on StartPaused(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if result := ticker.Inspect(); !result.Running || !result.Paused || result.Uptime <= 0 {
+		t.Errorf("expected a paused, running ticker with an uptime, got %+v", result)
+	}
+
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+	timer := backend.LastTimer()
+	timer.Advance(1)
+	<-ticker.C
+	timer.Advance(1)
+	timer.WaitForReader()
+	for deadline := time.Now().Add(5 * time.Second); ticker.Inspect().Dropped == 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the second tick to be dropped")
+		}
+	}
+	if result := ticker.Inspect(); !result.Running || result.Paused || result.Delivered != 1 || result.Dropped != 1 {
+		t.Errorf("expected a running ticker with 1 delivery and 1 drop, got %+v", result)
+	}
+
+	if err := ticker.Stop(); err != nil {
+		t.Fatalf("on Stop(): %s", err.Error())
+	}
+	if result := ticker.Inspect(); result.Running || result.Uptime != 0 {
+		t.Errorf("expected a stopped ticker, got %+v", result)
+	}
+}
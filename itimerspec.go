@@ -0,0 +1,105 @@
+package hrtime
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// GetItimerSpec returns the current setting of the running ticker's timerfd,
+// as reported by timerfd_gettime(2): Value is the time remaining until the
+// next expiration (zero if the timer is disarmed) and Interval is the period.
+func (ticker *Ticker[T]) GetItimerSpec() (*unix.ItimerSpec, error) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return nil, fmt.Errorf("cannot GetItimerSpec() of a stopped ticker")
+	}
+
+	return ticker.handles.timer.Gettime()
+}
+
+// SetItimerSpec arms the running ticker's timerfd directly with
+// timerfd_settime(2).  If abs is true, spec.Value is an absolute time on the
+// ticker's clock (TFD_TIMER_ABSTIME); otherwise it is relative to now.  A
+// zero spec.Value disarms the timer, and a zero spec.Interval makes it fire
+// only once.
+//
+// This is a low-level escape hatch.  The ticker's configured interval is not
+// changed, and the ticker is not considered to be paused even if the timer
+// is disarmed, so a later Reset() or Resume() replaces the setting made here.
+// The nominal schedule used to compute lateness is reset to match spec.
+func (ticker *Ticker[T]) SetItimerSpec(spec *unix.ItimerSpec, abs bool) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return fmt.Errorf("cannot SetItimerSpec() of a stopped ticker")
+	}
+
+	flags := 0
+	firstDeadline := time.Duration(spec.Value.Nano())
+	if abs {
+		flags = unix.TFD_TIMER_ABSTIME
+	} else {
+		now, err := ticker.handles.timer.Now()
+		if err != nil {
+			return err
+		}
+		firstDeadline = addSaturating(firstDeadline, now)
+	}
+
+	if err := ticker.handles.timer.Settime(flags, spec); err != nil {
+		return err
+	}
+
+	handles := ticker.handles
+	handles.mu.Lock()
+	handles.schedule = tickSchedule{
+		firstDeadline: firstDeadline,
+		interval:      time.Duration(spec.Interval.Nano()),
+	}
+	handles.mu.Unlock()
+
+	return nil
+}
+
+// addSaturating returns a + b, for a time and a non-negative duration,
+// limited to the greatest time.Duration rather than overflowing.  The kernel
+// limits timer arming in the same way, so for timers it is only reached by
+// intervals of centuries.
+func addSaturating(a time.Duration, b time.Duration) time.Duration {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+
+	return a + b
+}
+
+// SpecFor returns the spec and flags that Start() would pass to
+// timerfd_settime(2) to arm the timer of a ticker created with interval and
+// opts, without creating a timer, so that a configuration can be checked
+// without side effects.  It goes through the same code as Start(), so the
+// two cannot disagree, with two exceptions: the spec omits the random delay
+// of WithStartupJitter, which Start() adds to the first expiration; and, as a
+// rate-scheduled ticker (see WithRate) is armed for the absolute time of its
+// first tick (with TFD_TIMER_ABSTIME), its spec is computed from the current
+// time on the ticker's clock, so it is only what Start() would use if
+// called now.  Start() arms the timer the same way whatever the backend
+// (see WithBackend), although a backend other than timerfd applies the spec
+// in its own way.
+func SpecFor(interval time.Duration, opts ...Option) (*unix.ItimerSpec, int) {
+	ticker := newTicker(interval, opts, func(tickInfo) struct{} { return struct{}{} })
+
+	var armedAt time.Duration
+	if ticker.rate != 0 {
+		armedAt, _ = clockNow(int32(ticker.config.clock))
+	}
+
+	spec, flags, _ := ticker.arming(ticker.config.clock, armedAt, ticker.config.immediateFirstTick, 0)
+
+	return spec, flags
+}
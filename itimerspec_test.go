@@ -0,0 +1,94 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestItimerSpecAccess(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Hour)
+
+	if _, err := ticker.GetItimerSpec(); err == nil {
+		t.Errorf("expected error on GetItimerSpec() of stopped ticker")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	spec, err := ticker.GetItimerSpec()
+	if err != nil {
+		t.Fatalf("on GetItimerSpec(): %s", err.Error())
+	}
+	if interval := time.Duration(spec.Interval.Nano()); interval != time.Hour {
+		t.Errorf("expected Interval of 1h, got %s", interval)
+	}
+	if remaining := time.Duration(spec.Value.Nano()); remaining <= 59*time.Minute || remaining > time.Hour {
+		t.Errorf("expected Value just under 1h, got %s", remaining)
+	}
+
+	now := time.Duration(hrtime.MonotonicNow())
+	err = ticker.SetItimerSpec(&unix.ItimerSpec{
+		Value:    unix.NsecToTimespec(int64(now + 5*time.Millisecond)),
+		Interval: unix.NsecToTimespec(int64(2 * time.Millisecond)),
+	}, true)
+	if err != nil {
+		t.Fatalf("on SetItimerSpec(): %s", err.Error())
+	}
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after SetItimerSpec()")
+	}
+
+	if spec, err = ticker.GetItimerSpec(); err != nil {
+		t.Fatalf("on second GetItimerSpec(): %s", err.Error())
+	}
+	if interval := time.Duration(spec.Interval.Nano()); interval != 2*time.Millisecond {
+		t.Errorf("expected Interval of 2ms after SetItimerSpec(), got %s", interval)
+	}
+}
+
+func TestSpecFor(t *testing.T) {
+	// The spec matches the one that Start() uses.
+	for _, opts := range [][]hrtime.Option{
+		nil,
+		{hrtime.WithImmediateFirstTick()},
+		{hrtime.WithClock(hrtime.ClockBoottime)},
+	} {
+		spec, flags := hrtime.SpecFor(250*time.Millisecond, opts...)
+
+		backend := hrtimetest.NewBackend()
+		ticker := hrtime.NewMonotonicTicker(250*time.Millisecond, append(opts, hrtime.WithBackend(backend))...)
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+		started, startedFlags := backend.LastTimer().LastSettime()
+		ticker.Stop()
+
+		if *spec != started || flags != startedFlags {
+			t.Errorf("expected SpecFor() to return %+v with flags %d, as Start() used, got %+v with flags %d", started, startedFlags, *spec, flags)
+		}
+	}
+
+	if spec, flags := hrtime.SpecFor(time.Second); spec.Value.Nano() != int64(time.Second) || spec.Interval.Nano() != int64(time.Second) || flags != 0 {
+		t.Errorf("expected a relative periodic spec of 1s, got %+v with flags %d", *spec, flags)
+	}
+
+	// A rate-scheduled ticker is armed for the absolute time of its first
+	// tick.
+	before := hrtime.MonotonicNow()
+	spec, flags := hrtime.SpecFor(time.Hour, hrtime.WithRate(4))
+	if flags != unix.TFD_TIMER_ABSTIME || spec.Interval.Nano() != 0 {
+		t.Errorf("expected an absolute one-shot spec, got %+v with flags %d", *spec, flags)
+	}
+	if first := hrtime.MonotonicTime(spec.Value.Nano()).Sub(before); first < 250*time.Millisecond || first > 300*time.Millisecond {
+		t.Errorf("expected the first tick to be due 250ms from now, got %s", first)
+	}
+}
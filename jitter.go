@@ -0,0 +1,43 @@
+package hrtime
+
+import (
+	"math/rand"
+	"time"
+)
+
+// WithStartupJitter makes Start() delay the first tick by a random amount in
+// [0, max), so that tickers started at the same moment (across a fleet that
+// restarts together, for instance) do not tick in unison.  Only the initial
+// phase is affected: the ticks after the first follow at the interval, and
+// lateness is measured against the delayed schedule.  With
+// WithImmediateFirstTick, the immediate tick is the one that is delayed.
+// StartPaused(), Resume() and Reset() are not delayed.  The delay is drawn
+// from the source set by WithJitterSource, or from the default source of
+// math/rand.
+func WithStartupJitter(max time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.startupJitterMax = max
+	}
+}
+
+// WithJitterSource sets the source of the random delays of
+// WithStartupJitter, so that tests can make them predictable.  A rand.Source
+// is not safe for concurrent use, so each ticker should be given its own.
+func WithJitterSource(source rand.Source) Option {
+	return func(config *tickerConfig) {
+		config.jitterSource = rand.New(source)
+	}
+}
+
+// startupJitter returns the delay of the first tick after Start().
+func (config *tickerConfig) startupJitter() time.Duration {
+	if config.startupJitterMax <= 0 {
+		return 0
+	}
+
+	if config.jitterSource != nil {
+		return time.Duration(config.jitterSource.Int63n(int64(config.startupJitterMax)))
+	}
+
+	return time.Duration(rand.Int63n(int64(config.startupJitterMax)))
+}
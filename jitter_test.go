@@ -0,0 +1,58 @@
+package hrtime_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestStartupJitter(t *testing.T) {
+	const interval, max = 10 * time.Millisecond, 50 * time.Millisecond
+	expectedDelay := time.Duration(rand.New(rand.NewSource(7)).Int63n(int64(max)))
+
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(interval, hrtime.WithBackend(backend),
+		hrtime.WithStartupJitter(max), hrtime.WithJitterSource(rand.NewSource(7)))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	spec, _ := backend.LastTimer().LastSettime()
+	if first := time.Duration(spec.Value.Nano()); first != interval+expectedDelay {
+		t.Errorf("expected the first tick after %s, got %s", interval+expectedDelay, first)
+	}
+	if period := time.Duration(spec.Interval.Nano()); period != interval {
+		t.Errorf("expected later ticks to follow at %s, got %s", interval, period)
+	}
+
+	// Only Start() is delayed.
+	if err := ticker.Reset(interval); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	if spec, _ := backend.LastTimer().LastSettime(); time.Duration(spec.Value.Nano()) != interval {
+		t.Errorf("expected Reset() to arm the first tick after %s, got %s", interval, time.Duration(spec.Value.Nano()))
+	}
+}
+
+func TestStartupJitterIsWithinRange(t *testing.T) {
+	const interval, max = time.Millisecond, 5 * time.Millisecond
+
+	for i := 0; i < 100; i++ {
+		backend := hrtimetest.NewBackend()
+		ticker := hrtime.NewMonotonicTicker(interval, hrtime.WithBackend(backend),
+			hrtime.WithStartupJitter(max), hrtime.WithImmediateFirstTick())
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+		spec, _ := backend.LastTimer().LastSettime()
+		ticker.Stop()
+
+		if first := time.Duration(spec.Value.Nano()); first <= 0 || first > max {
+			t.Fatalf("expected the immediate tick to be delayed by less than %s, got %s", max, first)
+		}
+	}
+}
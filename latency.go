@@ -0,0 +1,53 @@
+package hrtime
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// minLatencyDelay is how far ahead MinLatency arms each sample.
+const minLatencyDelay = time.Microsecond
+
+// MinLatency measures the floor of the timer latency on this machine: it arms
+// a one-shot timerfd on CLOCK_MONOTONIC samples times, each for an absolute
+// time a microsecond ahead, and measures how long after that time the
+// calling goroutine could observe the expiration, waking through the Go
+// runtime's poller as a ticker's read goroutine does.  It returns the
+// smallest latency observed.  The typical latency, under load, is higher;
+// Stats() reports it for a running ticker.  MinLatency takes a sample even
+// if samples is less than 1, and closes its timerfd before it returns.
+func MinLatency(samples int) (time.Duration, error) {
+	timerFile, err := timerfdCreate(ClockMonotonic)
+	if err != nil {
+		return 0, err
+	}
+	defer timerFile.Close()
+
+	b := make([]byte, 8)
+	best := time.Duration(-1)
+	for i := 0; i < max(samples, 1); i++ {
+		now, err := clockNow(unix.CLOCK_MONOTONIC)
+		if err != nil {
+			return 0, err
+		}
+
+		deadline := now + minLatencyDelay
+		if err := settimeUsingFile(timerFile, unix.TFD_TIMER_ABSTIME, oneShotItimerSpec(deadline)); err != nil {
+			return 0, err
+		}
+		if _, err := timerFile.Read(b); err != nil {
+			return 0, err
+		}
+
+		wokeAt, err := clockNow(unix.CLOCK_MONOTONIC)
+		if err != nil {
+			return 0, err
+		}
+		if latency := wokeAt - deadline; best < 0 || latency < best {
+			best = latency
+		}
+	}
+
+	return best, nil
+}
@@ -0,0 +1,32 @@
+package hrtime_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestMinLatency(t *testing.T) {
+	fdsBefore, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatalf("on listing open fds: %s", err.Error())
+	}
+
+	latency, err := hrtime.MinLatency(50)
+	if err != nil {
+		t.Fatalf("on MinLatency(): %s", err.Error())
+	}
+	if latency < 0 || latency > 100*time.Millisecond {
+		t.Errorf("expected a plausible minimum latency, got %s", latency)
+	}
+
+	fdsAfter, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatalf("on listing open fds: %s", err.Error())
+	}
+	if len(fdsAfter) != len(fdsBefore) {
+		t.Errorf("expected MinLatency() to close the fds it opened, had %d open before and %d after", len(fdsBefore), len(fdsAfter))
+	}
+}
@@ -0,0 +1,81 @@
+package hrtime_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestLongIntervalsArmCorrectly(t *testing.T) {
+	for _, interval := range []time.Duration{
+		3 * time.Hour,
+		24 * time.Hour,
+		7*24*time.Hour + 999999999,
+		400 * 24 * time.Hour,
+	} {
+		backend := hrtimetest.NewBackend()
+		ticker := hrtime.NewMonotonicTicker(interval, hrtime.WithBackend(backend))
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() with an interval of %s: %s", interval, err.Error())
+		}
+
+		timer := backend.LastTimer()
+		spec, _ := timer.LastSettime()
+		wantSec, wantNsec := int64(interval/time.Second), int64(interval%time.Second)
+		if spec.Value.Sec != wantSec || spec.Value.Nsec != wantNsec || spec.Interval.Sec != wantSec || spec.Interval.Nsec != wantNsec {
+			t.Errorf("expected the timer to be armed with %ds + %dns for an interval of %s, got %+v", wantSec, wantNsec, interval, spec)
+		}
+
+		// The first tick arrives exactly one interval after Start(), on time.
+		if expirations := timer.AdvanceTime(interval - time.Nanosecond); expirations != 0 {
+			t.Errorf("expected no tick before %s, got %d", interval, expirations)
+		}
+		if expirations := timer.AdvanceTime(time.Nanosecond); expirations != 1 {
+			t.Errorf("expected a tick at %s, got %d", interval, expirations)
+		}
+		timer.WaitForReader()
+
+		if stats := ticker.Stats(); stats.Expirations != 1 || stats.MaxLateness != 0 {
+			t.Errorf("expected one tick on time after %s, got %+v", interval, stats)
+		}
+		ticker.Stop()
+	}
+}
+
+func TestMaximumIntervalArms(t *testing.T) {
+	const interval = time.Duration(math.MaxInt64)
+
+	for name, opts := range map[string][]hrtime.Option{
+		"timerfd":   nil,
+		"posix":     {hrtime.WithPOSIXTimers()},
+		"nanosleep": {hrtime.WithNanosleep()},
+	} {
+		ticker := hrtime.NewMonotonicTicker(interval, opts...)
+		if err := ticker.Start(); err != nil {
+			t.Errorf("on Start() of the %s backend with the maximum interval: %s", name, err.Error())
+			continue
+		}
+
+		spec, err := ticker.GetItimerSpec()
+		if err != nil {
+			t.Errorf("on GetItimerSpec() of the %s backend: %s", name, err.Error())
+		} else if remaining := time.Duration(spec.Value.Nano()); remaining < interval/2 {
+			t.Errorf("expected the %s backend to report about %s until the first tick, got %s", name, interval, remaining)
+		}
+
+		select {
+		case <-ticker.C:
+			t.Errorf("expected no tick from the %s backend with the maximum interval", name)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		// Resetting from a clock that has advanced must not overflow.
+		if err := ticker.Reset(interval); err != nil {
+			t.Errorf("on Reset() of the %s backend to the maximum interval: %s", name, err.Error())
+		}
+		ticker.Stop()
+	}
+}
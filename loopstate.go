@@ -0,0 +1,80 @@
+package hrtime
+
+import "fmt"
+
+// A LoopState describes what a ticker's read goroutine is doing, as reported
+// by LoopState().
+type LoopState int32
+
+const (
+	// LoopIdle means that no read goroutine is running: the ticker is
+	// stopped, or has never been started.
+	LoopIdle LoopState = iota
+
+	// LoopReading means that the read goroutine is waiting for the timer
+	// to expire.  A ticker that stays in this state is not ticking: it may
+	// be paused, or have a long interval.
+	LoopReading
+
+	// LoopProcessing means that the read goroutine is accounting for the
+	// expirations it has just read.  This is brief.
+	LoopProcessing
+
+	// LoopSending means that the read goroutine is delivering a tick.  A
+	// send on the channel never blocks (a tick that no receiver is ready
+	// for is carried over), so this is brief too, unless the ticker calls a
+	// handler from the read goroutine (see WithHandler), or waits for room
+	// in the queue of a handler pool (see QueueWhenBusy).  A ticker that
+	// stays in this state is held up by its consumer.
+	LoopSending
+)
+
+// String returns the name of the state, such as "LoopReading".
+func (state LoopState) String() string {
+	switch state {
+	case LoopIdle:
+		return "LoopIdle"
+	case LoopReading:
+		return "LoopReading"
+	case LoopProcessing:
+		return "LoopProcessing"
+	case LoopSending:
+		return "LoopSending"
+	default:
+		return fmt.Sprintf("LoopState(%d)", int32(state))
+	}
+}
+
+// LoopState returns what the ticker's read goroutine is doing at this moment,
+// to help diagnose a ticker whose consumer sees no ticks.  The read goroutine
+// records each change with a single atomic store.
+func (ticker *Ticker[T]) LoopState() LoopState {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return LoopIdle
+	}
+
+	return LoopState(handles.loopState.Load())
+}
+
+// Ready returns a channel that is closed once the ticker's current run is
+// live: its timer has been armed (by Start(); a run begun with StartPaused()
+// is not armed until Resume()) and its read goroutine is waiting for the
+// first expiration.  It is closed before the first tick is delivered, even
+// with WithImmediateFirstTick, so components that depend on the ticker can
+// wait on it rather than poll.  Each Start() creates a new channel, so Ready
+// should be called after Start(); it returns nil if the ticker has never been
+// started, and the closed channel of the last run if it is stopped.
+func (ticker *Ticker[T]) Ready() <-chan struct{} {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.handles == nil {
+		return nil
+	}
+
+	return ticker.handles.loopStarted
+}
@@ -0,0 +1,74 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestLoopState(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	inHandler, release := make(chan struct{}), make(chan struct{})
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {
+		inHandler <- struct{}{}
+		<-release
+	}))
+
+	if state := ticker.LoopState(); state != hrtime.LoopIdle {
+		t.Errorf("expected LoopIdle before Start(), got %s", state)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	timer := backend.LastTimer()
+	timer.WaitForReader()
+	if state := ticker.LoopState(); state != hrtime.LoopReading {
+		t.Errorf("expected LoopReading while waiting for the timer, got %s", state)
+	}
+
+	timer.Fire()
+	<-inHandler
+	if state := ticker.LoopState(); state != hrtime.LoopSending {
+		t.Errorf("expected LoopSending while the handler runs, got %s", state)
+	}
+	close(release)
+
+	ticker.Stop()
+	deadline := time.Now().Add(time.Second)
+	for ticker.LoopState() != hrtime.LoopIdle {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected LoopIdle after Stop(), got %s", ticker.LoopState())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTickerReady(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	if ready := ticker.Ready(); ready != nil {
+		t.Errorf("expected no Ready() channel before Start()")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the Ready() channel to be closed after Start()")
+	}
+	if state := ticker.LoopState(); state == hrtime.LoopIdle {
+		t.Errorf("expected the read goroutine to be running once ready, got %s", state)
+	}
+
+	select {
+	case <-ticker.C:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected ticks once the ticker was ready")
+	}
+}
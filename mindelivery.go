@@ -0,0 +1,51 @@
+package hrtime
+
+import "time"
+
+// WithMinDeliveryInterval makes the ticker attempt a delivery (a send on its
+// channel, or a call to its handler) at most once per d, so that it can count
+// ticks at a fine interval while notifying its consumer at a coarse one.  The
+// first tick is delivered as usual; after that, ticks read less than d after
+// the previous attempt are accumulated, and the next attempt delivers their
+// total.  Time is measured with the ticker's TimestampSource, at whole ticks,
+// so deliveries are at least d apart, and at most d plus one interval apart
+// (if the consumer is receiving).  The final tick of a countdown (see
+// WithCountdown) is delivered without waiting.
+//
+// This is the way to throttle a consumer's wake-ups, to save power: between
+// attempts, the read loop makes no attempt to send at all (not even one that
+// fails at once), so a consumer blocked on the channel is woken at most once
+// per d, however fine the interval, and the count that it receives still
+// includes every expiration.  The cost is precision: a tick can reach the
+// consumer up to d after it occurred, and the consumer only learns of the
+// ticks in batches, so it should use the count (or, for a RichTick, the
+// Cumulative count) rather than the arrival of values to keep time.  The read
+// goroutine itself still wakes for every expiration; for fewer wake-ups
+// overall, use a coarser interval.  BenchmarkMinDeliveryIntervalWakeups
+// measures the reduction.
+func WithMinDeliveryInterval(d time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.minDeliveryInterval = d
+	}
+}
+
+// deliveryDue reports whether the read loop should attempt to deliver tick.
+// lastAttempt holds the time of the previous attempt (zero if there has not
+// been one), and is updated when the result is true.
+func (ticker *Ticker[T]) deliveryDue(lastAttempt *MonotonicTime, tick *tickInfo) bool {
+	d := scaled(ticker.config.minDeliveryInterval)
+	if d <= 0 {
+		return true
+	}
+
+	if count := ticker.config.countdown; count > 0 && tick.cumulative == count {
+		return true
+	}
+
+	if *lastAttempt != 0 && tick.firedAt.Sub(*lastAttempt) < d {
+		return false
+	}
+
+	*lastAttempt = tick.firedAt
+	return true
+}
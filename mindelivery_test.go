@@ -0,0 +1,85 @@
+package hrtime_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+// fakeTimestamps is a TimestampSource that reads the fake clock of the most
+// recent timer created by a fake backend.
+type fakeTimestamps struct {
+	backend *hrtimetest.Backend
+}
+
+func (source fakeTimestamps) Now() hrtime.MonotonicTime {
+	now, _ := source.backend.LastTimer().Now()
+	return hrtime.MonotonicTime(now)
+}
+
+func TestTickerWithMinDeliveryInterval(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	var calls []uint64
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond,
+		hrtime.WithBackend(backend),
+		hrtime.WithTimestampSource(fakeTimestamps{backend}),
+		hrtime.WithMinDeliveryInterval(10*time.Millisecond),
+		hrtime.WithHandler(func(expirations uint64) {
+			calls = append(calls, expirations)
+		}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	for i := 0; i < 25; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+
+	if expected := []uint64{1, 10, 10}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected deliveries of %v ticks, got %v", expected, calls)
+	}
+}
+
+// BenchmarkMinDeliveryIntervalWakeups reports how often a consumer of a
+// ticker with a 100µs interval is woken, with and without a minimum delivery
+// interval of 5ms, and how many ticks each wake-up carries.
+func BenchmarkMinDeliveryIntervalWakeups(b *testing.B) {
+	const interval = 100 * time.Microsecond
+
+	for _, mode := range []struct {
+		name string
+		opts []hrtime.Option
+	}{
+		{"unthrottled", nil},
+		{"throttled", []hrtime.Option{hrtime.WithMinDeliveryInterval(5 * time.Millisecond)}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			ticker := hrtime.NewMonotonicTicker(interval, mode.opts...)
+			if err := ticker.Start(); err != nil {
+				b.Fatalf("on Start(): %s", err.Error())
+			}
+			defer ticker.Stop()
+
+			var ticks, wakeups uint64
+			b.ResetTimer()
+			startedAt := time.Now()
+			for ticks < uint64(b.N) {
+				ticks += <-ticker.C
+				wakeups++
+			}
+			elapsed := time.Since(startedAt)
+			b.StopTimer()
+
+			b.ReportMetric(float64(wakeups)/elapsed.Seconds(), "wakeups/s")
+			b.ReportMetric(float64(ticks)/float64(wakeups), "ticks/wakeup")
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// WithNanosleep makes the ticker pace its ticks by sleeping with
+// clock_nanosleep(2) until each absolute deadline, instead of reading a
+// timerfd.  For intervals of a few microseconds, this can give tighter timing
+// on some kernels, because the sleeping thread is woken directly rather than
+// through the runtime's poller and a goroutine handoff.  The cost is that the
+// read goroutine occupies an OS thread while it sleeps, and that a re-arming
+// (by Reset() or Resume(), for instance) which moves the next tick earlier
+// only takes effect when the current sleep ends.  Sleeps last at most 10ms,
+// so this is prompt even with long intervals.  BenchmarkTickJitter compares
+// the jitter of the two backends.
+func WithNanosleep() Option {
+	return func(config *tickerConfig) {
+		config.backend = nanosleepBackend{}
+	}
+}
+
+// nanosleepSlice is the longest single sleep made by a nanosleepTimer.
+const nanosleepSlice = 10 * time.Millisecond
+
+// nanosleepBackend is the Backend selected by WithNanosleep.
+type nanosleepBackend struct{}
+
+func (nanosleepBackend) NewTimer(clock ClockID) (BackendTimer, error) {
+	// Fail early, as timerfd_create(2) would, if the clock cannot be read.
+	if _, err := clockNow(int32(clock)); err != nil {
+		if errors.Is(err, unix.EINVAL) {
+			return nil, fmt.Errorf("%w (%s): %w", ErrUnsupportedClock, clock, err)
+		}
+		return nil, err
+	}
+
+	timer := &nanosleepTimer{clock: clock}
+	timer.cond = sync.NewCond(&timer.mu)
+
+	return timer, nil
+}
+
+// nanosleepTimer is a BackendTimer that keeps its arming in memory, and whose
+// Read sleeps until the next deadline.  Read re-examines the arming each time
+// it wakes, so a Settime made while it sleeps takes effect at the end of the
+// current slice.
+type nanosleepTimer struct {
+	clock ClockID
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	deadline time.Duration
+	interval time.Duration
+	closed   bool
+}
+
+func (timer *nanosleepTimer) Settime(flags int, spec *unix.ItimerSpec) error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return os.ErrClosed
+	}
+
+	deadline := time.Duration(spec.Value.Nano())
+	if deadline != 0 && flags&unix.TFD_TIMER_ABSTIME == 0 {
+		now, err := timer.Now()
+		if err != nil {
+			return err
+		}
+		deadline = addSaturating(deadline, now)
+	}
+
+	timer.deadline = deadline
+	timer.interval = time.Duration(spec.Interval.Nano())
+	timer.cond.Broadcast()
+
+	return nil
+}
+
+func (timer *nanosleepTimer) Gettime() (*unix.ItimerSpec, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return nil, os.ErrClosed
+	}
+
+	spec := &unix.ItimerSpec{Interval: unix.NsecToTimespec(int64(timer.interval))}
+	if timer.deadline != 0 {
+		now, err := timer.Now()
+		if err != nil {
+			return nil, err
+		}
+		// A deadline that has passed but has not been read is reported
+		// as 1ns away, because a zero value means that the timer is
+		// disarmed.
+		spec.Value = unix.NsecToTimespec(int64(max(timer.deadline-now, 1)))
+	}
+
+	return spec, nil
+}
+
+func (timer *nanosleepTimer) Read() (uint64, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	for {
+		for timer.deadline == 0 && !timer.closed {
+			timer.cond.Wait()
+		}
+		if timer.closed {
+			return 0, os.ErrClosed
+		}
+
+		now, err := timer.Now()
+		if err != nil {
+			return 0, err
+		}
+
+		if now >= timer.deadline {
+			return timer.expire(now), nil
+		}
+
+		wakeAt := min(timer.deadline, now+nanosleepSlice)
+		timer.mu.Unlock()
+		request := unix.NsecToTimespec(int64(wakeAt))
+		err = unix.ClockNanosleep(int32(timer.clock), unix.TIMER_ABSTIME, &request, nil)
+		timer.mu.Lock()
+
+		// EINTR is harmless: the loop recomputes how long is left to sleep.
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return 0, err
+		}
+	}
+}
+
+// expire must be called with the lock held, once now has reached the
+// deadline.  It returns the number of expirations up to now, and advances
+// (or, for a one-shot timer, clears) the deadline.
+func (timer *nanosleepTimer) expire(now time.Duration) uint64 {
+	if timer.interval == 0 {
+		timer.deadline = 0
+		return 1
+	}
+
+	expirations := uint64((now-timer.deadline)/timer.interval) + 1
+	timer.deadline += time.Duration(expirations) * timer.interval
+
+	return expirations
+}
+
+func (timer *nanosleepTimer) ReadPending() (uint64, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return 0, os.ErrClosed
+	}
+	if timer.deadline == 0 {
+		return 0, nil
+	}
+
+	now, err := timer.Now()
+	if err != nil || now < timer.deadline {
+		return 0, err
+	}
+
+	return timer.expire(now), nil
+}
+
+func (timer *nanosleepTimer) Now() (time.Duration, error) {
+	return clockNow(int32(timer.clock))
+}
+
+func (timer *nanosleepTimer) Close() error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return os.ErrClosed
+	}
+	timer.closed = true
+	timer.cond.Broadcast()
+
+	return nil
+}
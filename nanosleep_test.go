@@ -0,0 +1,76 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestMonotonicTickerWithNanosleep(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithNanosleep())
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	if err := ticker.Reset(500 * time.Microsecond); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+
+	ticks := uint64(0)
+	timeout := time.After(time.Second)
+	for ticks < 100 {
+		select {
+		case expirations := <-ticker.C:
+			ticks += expirations
+		case <-timeout:
+			t.Fatalf("expected 100 ticks within one second, got %d", ticks)
+		}
+	}
+
+	stoppedAt := time.Now()
+	ticker.Stop()
+	for range ticker.C {
+	}
+	if elapsed := time.Since(stoppedAt); elapsed > 100*time.Millisecond {
+		t.Errorf("expected channel to close promptly after Stop(), took %s", elapsed)
+	}
+}
+
+// BenchmarkTickJitter reports the mean and greatest lateness of ticks at
+// short intervals, for the timerfd and clock_nanosleep backends.
+func BenchmarkTickJitter(b *testing.B) {
+	backends := []struct {
+		name string
+		opts []hrtime.Option
+	}{
+		{"timerfd", nil},
+		{"nanosleep", []hrtime.Option{hrtime.WithNanosleep()}},
+	}
+
+	for _, interval := range []time.Duration{5 * time.Microsecond, 50 * time.Microsecond} {
+		for _, backend := range backends {
+			b.Run(backend.name+"/"+interval.String(), func(b *testing.B) {
+				done := make(chan struct{})
+				ticks := 0
+				ticker := hrtime.NewCountdownTicker(interval, uint64(b.N), func() { close(done) },
+					append(backend.opts, hrtime.WithHandler(func(expirations uint64) {
+						ticks++
+					}))...)
+
+				b.ResetTimer()
+				if err := ticker.Start(); err != nil {
+					b.Fatalf("on Start(): %s", err.Error())
+				}
+				<-done
+				b.StopTimer()
+
+				stats := ticker.Stats()
+				b.ReportMetric(float64(stats.MeanLateness.Nanoseconds()), "ns-mean-late")
+				b.ReportMetric(float64(stats.MaxLateness.Nanoseconds()), "ns-max-late")
+				b.ReportMetric(float64(b.N)/float64(ticks), "ticks/delivery")
+			})
+		}
+	}
+}
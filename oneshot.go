@@ -0,0 +1,150 @@
+package hrtime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A OneShotTimer is a high-resolution, reusable one-shot timer, like a
+// time.Timer that is re-armed with Reset(): it keeps a single timerfd for its
+// whole life, however often it is armed, stopped and re-armed, so a watchdog
+// that is reset on every sign of activity creates no file descriptors.
+//
+// Each expiration delivers its time (as observed by the timer's goroutine,
+// on CLOCK_MONOTONIC) on C, which is buffered, so that an expiration is not
+// lost while nobody is receiving.  Reset() and Stop() discard an expiration
+// that has not yet been received, and one that is being delivered as they
+// are called, so C never delivers the expiration of an earlier arming.
+type OneShotTimer struct {
+	// C delivers the time of each expiration.  It is closed by Close().
+	C <-chan MonotonicTime
+
+	c      chan MonotonicTime
+	file   *os.File
+	mu     sync.Mutex
+	armed  bool
+	closed bool
+}
+
+// NewOneShotTimer creates a disarmed OneShotTimer on the provided clock, and
+// starts the goroutine that waits for its expirations.  Close() releases the
+// timerfd and the goroutine.
+func NewOneShotTimer(clock ClockID) (*OneShotTimer, error) {
+	file, err := timerfdCreate(clock)
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan MonotonicTime, 1)
+	timer := &OneShotTimer{C: c, c: c, file: file}
+	go timer.readLoop()
+
+	return timer, nil
+}
+
+// Reset arms the timer to expire once, d from now, discarding any expiration
+// of an earlier arming that has not been received.  It can be called whether
+// the timer is armed, stopped or has expired.  A d of 0 or less makes it
+// expire at once.  It returns an error if the timer is closed.
+func (timer *OneShotTimer) Reset(d time.Duration) error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return fmt.Errorf("cannot Reset() a closed timer")
+	}
+	timer.armed = false
+	timer.drain()
+
+	if err := settimeUsingFile(timer.file, 0, &unix.ItimerSpec{Value: unix.NsecToTimespec(int64(max(scaled(d), immediateTickDelay)))}); err != nil {
+		return err
+	}
+	timer.armed = true
+
+	return nil
+}
+
+// Stop disarms the timer, without closing it, and discards any expiration
+// that has not been received.  It returns true if the timer was armed and
+// had not yet expired.
+func (timer *OneShotTimer) Stop() (bool, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return false, fmt.Errorf("cannot Stop() a closed timer")
+	}
+	timer.drain()
+
+	wasPending := false
+	if timer.armed {
+		spec, err := gettimeUsingFile(timer.file)
+		if err != nil {
+			return false, err
+		}
+		wasPending = spec.Value.Nano() != 0
+	}
+	timer.armed = false
+
+	if err := settimeUsingFile(timer.file, 0, &unix.ItimerSpec{}); err != nil {
+		return false, err
+	}
+
+	return wasPending, nil
+}
+
+// Close disarms the timer, closes its timerfd, and closes C once its
+// goroutine has exited.
+func (timer *OneShotTimer) Close() error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.closed {
+		return nil
+	}
+	timer.closed = true
+	timer.armed = false
+
+	return timer.file.Close()
+}
+
+// drain discards an expiration waiting on the channel.  It is called with
+// the lock held.
+func (timer *OneShotTimer) drain() {
+	select {
+	case <-timer.c:
+	default:
+	}
+}
+
+// readLoop delivers the timer's expirations until it is closed.  An
+// expiration is only delivered if it belongs to the current arming: the
+// timer is still armed, and its timerfd reports no pending expiration (a
+// Reset() between the read and the delivery re-arms it, so its expiration is
+// pending again).
+func (timer *OneShotTimer) readLoop() {
+	defer close(timer.c)
+
+	b := make([]byte, 8)
+	for {
+		if _, err := timer.file.Read(b); err != nil {
+			return
+		}
+		firedAt := MonotonicNow()
+
+		timer.mu.Lock()
+		if timer.armed {
+			spec, err := gettimeUsingFile(timer.file)
+			if err == nil && spec.Value.Nano() == 0 {
+				timer.armed = false
+				timer.drain()
+				timer.c <- firedAt
+			}
+		}
+		timer.mu.Unlock()
+	}
+}
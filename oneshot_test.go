@@ -0,0 +1,91 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestOneShotTimer(t *testing.T) {
+	timer, err := hrtime.NewOneShotTimer(hrtime.ClockMonotonic)
+	if err != nil {
+		t.Fatalf("on NewOneShotTimer(): %s", err.Error())
+	}
+	defer timer.Close()
+
+	armedAt := hrtime.MonotonicNow()
+	if err := timer.Reset(5 * time.Millisecond); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	select {
+	case firedAt := <-timer.C:
+		if elapsed := firedAt.Sub(armedAt); elapsed < 5*time.Millisecond {
+			t.Errorf("expected the timer to expire after 5ms, it expired after %s", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the timer to expire")
+	}
+
+	// A Reset() after expiry re-arms the timer.
+	if err := timer.Reset(time.Millisecond); err != nil {
+		t.Fatalf("on Reset() after expiry: %s", err.Error())
+	}
+	select {
+	case <-timer.C:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the timer to expire again after Reset()")
+	}
+
+	// An expiration that was not received is discarded by Reset().
+	if err := timer.Reset(time.Millisecond); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := timer.Reset(time.Hour); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	select {
+	case <-timer.C:
+		t.Errorf("expected Reset() to discard the expiration of the previous arming")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Stop() disarms the timer, and reports whether it was armed.
+	if wasPending, err := timer.Stop(); err != nil || !wasPending {
+		t.Errorf("expected Stop() of an armed timer to return true, got %t, %v", wasPending, err)
+	}
+	if wasPending, err := timer.Stop(); err != nil || wasPending {
+		t.Errorf("expected Stop() of a stopped timer to return false, got %t, %v", wasPending, err)
+	}
+}
+
+func TestOneShotTimerWatchdog(t *testing.T) {
+	timer, err := hrtime.NewOneShotTimer(hrtime.ClockMonotonic)
+	if err != nil {
+		t.Fatalf("on NewOneShotTimer(): %s", err.Error())
+	}
+
+	// Resetting the timer faster than it expires keeps it from expiring.
+	for i := 0; i < 200; i++ {
+		if err := timer.Reset(20 * time.Millisecond); err != nil {
+			t.Fatalf("on Reset() %d: %s", i, err.Error())
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	select {
+	case <-timer.C:
+		t.Fatalf("expected the watchdog not to expire while it was being reset")
+	default:
+	}
+
+	if err := timer.Close(); err != nil {
+		t.Fatalf("on Close(): %s", err.Error())
+	}
+	if _, isOpen := <-timer.C; isOpen {
+		t.Errorf("expected C to be closed by Close()")
+	}
+	if err := timer.Reset(time.Millisecond); err == nil {
+		t.Errorf("expected Reset() of a closed timer to fail")
+	}
+}
@@ -0,0 +1,50 @@
+package hrtime
+
+import "errors"
+
+// ErrCountdownComplete is the reason passed to the OnStop() callback when
+// a ticker stops itself at the end of its countdown (see WithCountdown).
+var ErrCountdownComplete = errors.New("countdown complete")
+
+// ErrDurationElapsed is the reason passed to the OnStop() callback when a
+// ticker stops itself at the end of its duration (see WithDuration).
+var ErrDurationElapsed = errors.New("duration elapsed")
+
+// OnStop sets f to be called each time a run of the ticker ends, whatever
+// ended it, replacing any callback set before.  f is called exactly once per
+// run, from the read goroutine as it exits, after the channel has been
+// closed, so a consumer ranging over the channel sees the closure first.
+// reason says why the run ended:
+//
+//   - nil, after Stop() (or StopWithResidual()).
+//   - ErrCountdownComplete or ErrDurationElapsed, when the ticker stopped
+//     itself (after the onDone of WithCountdown has returned).
+//   - the cause of the context of WithBaseContext, when it is done.
+//   - an error wrapping the failure, if reading the timer failed.
+//
+// The run has finished delivering ticks by the time f is called, so f may call
+// Start(), and a Start() made elsewhere may already have begun a new run.
+func (ticker *Ticker[T]) OnStop(f func(reason error)) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	ticker.onStop = f
+}
+
+// runOnStop calls the OnStop() callback, if there is one, for the run of
+// handles, which must be closed.
+func (ticker *Ticker[T]) runOnStop(handles *tickerHandles[T]) {
+	ticker.mu.Lock()
+	onStop := ticker.onStop
+	ticker.mu.Unlock()
+
+	if onStop == nil {
+		return
+	}
+
+	handles.mu.Lock()
+	reason := handles.stopReason
+	handles.mu.Unlock()
+
+	onStop(reason)
+}
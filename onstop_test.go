@@ -0,0 +1,84 @@
+package hrtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestOnStop(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	reasons := make(chan error, 10)
+	ticker.OnStop(func(reason error) {
+		select {
+		case <-ticker.C:
+		default:
+			t.Errorf("expected the channel to be closed before the OnStop() callback")
+		}
+		reasons <- reason
+	})
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	ticker.Stop()
+	ticker.Stop()
+
+	if reason := <-reasons; reason != nil {
+		t.Errorf("expected a nil reason after Stop(), got %v", reason)
+	}
+	select {
+	case reason := <-reasons:
+		t.Errorf("expected the callback to be called once, got another call with %v", reason)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestOnStopReasons(t *testing.T) {
+	countdown := hrtime.NewCountdownTicker(time.Millisecond, 2, nil)
+	duration := hrtime.NewDurationTicker(time.Millisecond, 5*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	withContext := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBaseContext(ctx))
+	backend := hrtimetest.NewBackend()
+	failing := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend))
+
+	for name, test := range map[string]struct {
+		ticker *hrtime.MonotonicTicker
+		end    func()
+		reason error
+	}{
+		"countdown": {countdown, func() {}, hrtime.ErrCountdownComplete},
+		"duration":  {duration, func() {}, hrtime.ErrDurationElapsed},
+		"context":   {withContext, cancel, context.Canceled},
+		"failure":   {failing, func() { backend.LastTimer().Close() }, errors.New("any")},
+	} {
+		reasons := make(chan error, 1)
+		test.ticker.OnStop(func(reason error) { reasons <- reason })
+		if err := test.ticker.Start(); err != nil {
+			t.Fatalf("on Start() of the %s ticker: %s", name, err.Error())
+		}
+		go func(c <-chan uint64) {
+			for range c {
+			}
+		}(test.ticker.C)
+		test.end()
+
+		select {
+		case reason := <-reasons:
+			if name == "failure" {
+				if reason == nil {
+					t.Errorf("expected a non-nil reason when the timer fails")
+				}
+			} else if !errors.Is(reason, test.reason) {
+				t.Errorf("expected the %s ticker to stop with %v, got %v", name, test.reason, reason)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("expected the %s ticker to stop", name)
+		}
+		test.ticker.Stop()
+	}
+}
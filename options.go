@@ -0,0 +1,195 @@
+package hrtime
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// An Option changes the behavior of a ticker.  Options are provided to the
+// ticker constructor.
+type Option func(*tickerConfig)
+
+type tickerConfig struct {
+	clock                 ClockID
+	backend               Backend
+	retainFD              bool
+	externalDriven        bool
+	overrunPauseThreshold uint64
+	onOverrunPause        func(undelivered uint64)
+	overrunAlarmFraction  float64
+	overrunAlarmWindow    time.Duration
+	onOverrunAlarm        func(observed float64)
+	timestamps            TimestampSource
+	onRawRead             func(expirations uint64)
+	stepThreshold         time.Duration
+	onStep                func(step ClockStep)
+	suspendThreshold      time.Duration
+	onResume              func(suspend Suspend)
+	handler               func(ctx context.Context, expirations uint64)
+	handlerStats          bool
+	recoverHandlerPanics  bool
+	completionPacing      bool
+	minPacedInterval      time.Duration
+	warmupTicks           uint64
+	wakeupLatency         bool
+	slowHandlerFraction   float64
+	onSlowHandler         func(elapsed time.Duration)
+	handlerWorkers        int
+	handlerQueueLength    int
+	whenHandlersBusy      BusyPolicy
+	rate                  uint64
+	immediateFirstTick    bool
+	startupJitterMax      time.Duration
+	jitterSource          *rand.Rand
+	skipMissed            bool
+	strict                bool
+	stopOnOverrun         bool
+	minDeliveryInterval   time.Duration
+	deliveryPredicate     func() bool
+	consumerIdleTimeout   time.Duration
+	ringSize              int
+	stopSentinel          bool
+	adaptiveEnterAbove    float64
+	adaptiveExitBelow     float64
+	adaptiveWindow        time.Duration
+	onDeliveryModeSwitch  func(mode DeliveryMode)
+	deliveryMode          DeliveryMode
+	handoffWait           time.Duration
+	separateSender        bool
+	coalesceThreshold     uint64
+	onCoalesce            func(count uint64)
+	onCPUQuotaRisk        func(requestedRate, maxRate float64)
+	recentIntervals       int
+	countdown             uint64
+	onCountdownDone       func()
+	duration              time.Duration
+	heartbeatEvery        uint64
+	baseContext           context.Context
+}
+
+func newTickerConfig(opts []Option) tickerConfig {
+	config := tickerConfig{
+		clock:           DefaultClock(),
+		backend:         timerfdBackend{},
+		timestamps:      clockGettimeSource{},
+		recentIntervals: defaultRecentIntervals,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// withOptions returns opts followed by extra, in a new slice, so that the
+// constructors that add options of their own never write to the backing
+// array of the caller's opts.
+func withOptions(opts []Option, extra ...Option) []Option {
+	combined := make([]Option, 0, len(opts)+len(extra))
+	return append(append(combined, opts...), extra...)
+}
+
+// WithClock sets the kernel clock that drives the ticker, in place of the
+// default (see SetDefaultClock).  If the kernel does not support timers on
+// the clock, Start() returns an error wrapping ErrUnsupportedClock.
+func WithClock(clock ClockID) Option {
+	return func(config *tickerConfig) {
+		config.clock = clock
+	}
+}
+
+// WithOverrunPause makes the ticker pause itself when the consumer falls too
+// far behind.  If more than threshold ticks have accumulated without being
+// read from the channel, the ticker is paused (as if Pause() had been called)
+// and onPause -- if it is not nil -- is called with the number of undelivered
+// ticks.  The undelivered ticks are not discarded; they are delivered with the
+// first tick after Resume().  onPause is called from the ticker's read
+// goroutine, so it must not block for long.  A threshold of 0 (the default)
+// disables this.
+func WithOverrunPause(threshold uint64, onPause func(undelivered uint64)) Option {
+	return func(config *tickerConfig) {
+		config.overrunPauseThreshold = threshold
+		config.onOverrunPause = onPause
+	}
+}
+
+// WithTimestampSource sets the source used to timestamp ticks.  By default,
+// CLOCK_MONOTONIC is read with clock_gettime.  See TSCSource for a cheaper,
+// but less portable, alternative.
+func WithTimestampSource(source TimestampSource) Option {
+	return func(config *tickerConfig) {
+		config.timestamps = source
+	}
+}
+
+// WithRawReadHook makes the ticker call onRead with the expiration count of
+// every successful read of its timer, as the kernel reported it: before the
+// count is combined with ticks that were not delivered, or adjusted by
+// WithRate, WithCountdown or WithSkipMissed.  This exposes the timerfd's own
+// accounting, for debugging; a value greater than 1 means that the timer
+// expired more than once between reads.  onRead is called from the read
+// goroutine, before the tick is processed, so it must be cheap and must not
+// block.
+func WithRawReadHook(onRead func(expirations uint64)) Option {
+	return func(config *tickerConfig) {
+		config.onRawRead = onRead
+	}
+}
+
+// WithStopSentinel makes Stop() send a final sentinel value, the zero value
+// of the ticker's type, on the channel just before closing it, so that a
+// consumer can tell a deliberate Stop() (a sentinel, then the closure) from a
+// run that ended on its own, or failed (the closure alone).  For a
+// MonotonicTicker, the sentinel is a count of 0, which is otherwise never
+// delivered, as every value counts at least one tick; for a RichTicker, it is
+// a RichTick with a Delta of 0.  No ticks are sent after the sentinel.
+//
+// Like every value, the sentinel is only sent if a receiver is ready for it,
+// so that Stop() never blocks: a consumer that is busy when Stop() is called
+// sees only the closure.  Only Stop() and StopWithResidual() send it, and
+// not for a ticker that uses a handler (see WithHandler) or a ring buffer
+// (see WithRingBuffer).
+func WithStopSentinel() Option {
+	return func(config *tickerConfig) {
+		config.stopSentinel = true
+	}
+}
+
+// WithSkipMissed makes the ticker discard the ticks that it could not send.
+// By default, when no receiver is ready for a value, its ticks are coalesced
+// into the next value sent, so after the consumer stalls it receives the
+// whole backlog as one count.  With WithSkipMissed, a value that cannot be
+// sent is dropped along with its ticks, so each value that is received
+// describes only the expirations read from the timer since the previous
+// attempt to send: after a stall, the consumer gets the next fresh tick,
+// normally with a count of 1.  A count greater than 1 is still possible if
+// the read goroutine itself falls behind the timer.  Skipped ticks are still
+// included in the Cumulative count of a RichTick, and each dropped value is
+// counted in Stats.Drops.  A ticker with a handler (see WithHandler) only
+// drops ticks if it uses a handler pool with DropWhenBusy.  With
+// WithSkipMissed, the threshold of WithOverrunPause is never reached.
+func WithSkipMissed() Option {
+	return func(config *tickerConfig) {
+		config.skipMissed = true
+	}
+}
+
+// The delay before the first expiration of a ticker started with
+// WithImmediateFirstTick.  A zero delay would disarm the timer.
+const immediateTickDelay = time.Nanosecond
+
+// WithImmediateFirstTick makes Start() arm the timer to expire right away,
+// rather than one interval later, so that the first tick is delivered as soon
+// as the ticker has started; the ticks after it follow at the interval.  The
+// immediate tick is an ordinary expiration of the timer, so it is counted
+// like any other: in the Cumulative count and Seq of a RichTick, in handler
+// calls and HandlerStats, and in lateness (measured against a nominal time of
+// Start()).  Only Start() ticks immediately; StartPaused(), Resume() and
+// Reset() schedule the first tick one interval later, as usual.
+func WithImmediateFirstTick() Option {
+	return func(config *tickerConfig) {
+		config.immediateFirstTick = true
+	}
+}
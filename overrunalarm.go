@@ -0,0 +1,109 @@
+package hrtime
+
+import "time"
+
+// WithOverrunAlarm makes the ticker call onAlarm when the fraction of its
+// deliveries that are dropped (see Stats.Drops) over the last window rises
+// above fraction.  onAlarm receives the observed fraction.  It is called
+// when the fraction crosses the threshold, not for every drop while it stays
+// above, and the alarm is re-armed once the fraction falls back to the
+// threshold or below.  onAlarm is called from the read goroutine, so it must
+// not block for long.
+//
+// The window is divided into a fixed number of buckets, so the memory used
+// does not depend on the tick rate, and the window slides in steps of one
+// bucket (a tenth of window).
+func WithOverrunAlarm(fraction float64, window time.Duration, onAlarm func(observed float64)) Option {
+	return func(config *tickerConfig) {
+		config.overrunAlarmFraction = fraction
+		config.overrunAlarmWindow = window
+		config.onOverrunAlarm = onAlarm
+	}
+}
+
+const overrunAlarmBuckets = 10
+
+// A slidingRatio tracks the fraction of events that were hits over a sliding
+// window, in a ring of buckets, so the memory used does not depend on the
+// event rate.  It is only used by the read goroutine.
+type slidingRatio struct {
+	bucketWidth time.Duration
+	buckets     [overrunAlarmBuckets]slidingRatioBucket
+}
+
+// A slidingRatioBucket counts the events in the bucketWidth interval with the
+// given index (counting from the clock's zero).
+type slidingRatioBucket struct {
+	index  int64
+	events uint64
+	hits   uint64
+}
+
+func newSlidingRatio(window time.Duration) slidingRatio {
+	return slidingRatio{bucketWidth: max(window/overrunAlarmBuckets, 1)}
+}
+
+// record counts an event at the provided time, and returns the fraction of
+// the events over the window that were hits.
+func (ratio *slidingRatio) record(at MonotonicTime, hit bool) float64 {
+	index := int64(time.Duration(at) / ratio.bucketWidth)
+	bucket := &ratio.buckets[index%overrunAlarmBuckets]
+	if bucket.index != index {
+		*bucket = slidingRatioBucket{index: index}
+	}
+	bucket.events++
+	if hit {
+		bucket.hits++
+	}
+
+	var events, hits uint64
+	for _, bucket := range ratio.buckets {
+		if bucket.index > index-overrunAlarmBuckets {
+			events += bucket.events
+			hits += bucket.hits
+		}
+	}
+
+	return float64(hits) / float64(events)
+}
+
+// reset forgets every event recorded.
+func (ratio *slidingRatio) reset() {
+	ratio.buckets = [overrunAlarmBuckets]slidingRatioBucket{}
+}
+
+// An overrunAlarm tracks the drop ratio of a run over a sliding window.  It
+// is only used by the read goroutine.
+type overrunAlarm struct {
+	fraction float64
+	onAlarm  func(observed float64)
+	drops    slidingRatio
+	raised   bool
+}
+
+// newOverrunAlarm returns nil if the overrun alarm is not configured.
+func newOverrunAlarm(config *tickerConfig) *overrunAlarm {
+	if config.onOverrunAlarm == nil || config.overrunAlarmWindow <= 0 {
+		return nil
+	}
+
+	return &overrunAlarm{
+		fraction: config.overrunAlarmFraction,
+		onAlarm:  config.onOverrunAlarm,
+		drops:    newSlidingRatio(config.overrunAlarmWindow),
+	}
+}
+
+// record counts a delivery attempt made at the provided time, then raises
+// or re-arms the alarm according to the drop ratio over the window.
+func (alarm *overrunAlarm) record(at MonotonicTime, dropped bool) {
+	observed := alarm.drops.record(at, dropped)
+	if observed > alarm.fraction {
+		if !alarm.raised {
+			alarm.raised = true
+			alarm.onAlarm(observed)
+		}
+	} else {
+		alarm.raised = false
+	}
+}
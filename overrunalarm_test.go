@@ -0,0 +1,66 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerWithOverrunAlarm(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	var alarms []float64
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond,
+		hrtime.WithBackend(backend),
+		hrtime.WithTimestampSource(fakeTimestamps{backend}),
+		hrtime.WithOverrunAlarm(0.5, 10*time.Millisecond, func(observed float64) {
+			alarms = append(alarms, observed)
+		}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// With no receiver, every delivery is dropped, but the alarm is only
+	// raised once.
+	timer := backend.LastTimer()
+	for i := 0; i < 5; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	if len(alarms) != 1 || alarms[0] != 1 {
+		t.Fatalf("expected one alarm with a drop ratio of 1, got %v", alarms)
+	}
+
+	// Once the window slides past the drops, the alarm is re-armed.
+	stopReceiving := make(chan struct{})
+	received := make(chan struct{})
+	go func() {
+		defer close(received)
+		for {
+			select {
+			case <-ticker.C:
+			case <-stopReceiving:
+				return
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+		time.Sleep(100 * time.Microsecond)
+	}
+	close(stopReceiving)
+	<-received
+
+	for i := 0; i < 20; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	if len(alarms) != 2 {
+		t.Errorf("expected the alarm to be raised again after recovering, got %v", alarms)
+	}
+}
@@ -0,0 +1,73 @@
+package hrtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// A PacingOverrunError reports that the handler of a completion-paced ticker
+// (see WithCompletionPacing) took so long that the time left before its next
+// call was below the minimum interval, so its next completion will be late.
+type PacingOverrunError struct {
+	// Elapsed is how long the handler call took.
+	Elapsed time.Duration
+
+	// Interval is the ticker's interval.
+	Interval time.Duration
+}
+
+func (err *PacingOverrunError) Error() string {
+	return fmt.Sprintf("handler took %s of a %s interval", err.Elapsed, err.Interval)
+}
+
+// WithCompletionPacing makes a ticker with a handler (see WithHandler) space
+// the completions of its handler calls evenly, at the interval, rather than
+// their starts.  After each call, the read goroutine measures how long the
+// call took, with the ticker's TimestampSource, and re-arms the timer to
+// expire after the interval less that time, so that, if the next call takes
+// as long, it completes one interval after this one did.  If the handler
+// takes so long that less than minInterval would be left, the timer is armed
+// to expire after minInterval instead (or, if minInterval is not positive,
+// right away), and a *PacingOverrunError is reported on Errors().
+//
+// Lateness and the other statistics are measured against the re-armed
+// timer.  Pause(), Resume(), Reset() and the like arm the timer as usual;
+// pacing resumes after the next handler call.  It applies neither to a
+// handler pool (see WithHandlerPool) nor to a rate-scheduled ticker (see
+// WithRate), nor to a ticker without a handler.
+func WithCompletionPacing(minInterval time.Duration) Option {
+	return func(config *tickerConfig) {
+		config.completionPacing = true
+		config.minPacedInterval = minInterval
+	}
+}
+
+// completionPaced reports whether the handler calls of a run are paced by
+// their completions.
+func (ticker *Ticker[T]) completionPaced(handles *tickerHandles[T]) bool {
+	return ticker.config.completionPacing && ticker.config.handler != nil && handles.handlerCalls == nil
+}
+
+// pace re-arms the timer of a completion-paced ticker after a handler call
+// that took elapsed.
+func (ticker *Ticker[T]) pace(handles *tickerHandles[T], elapsed time.Duration) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || ticker.inPausedState || ticker.handles != handles || ticker.rate != 0 {
+		return
+	}
+
+	interval := scaled(ticker.desiredInterval)
+	next := interval - elapsed
+	if minInterval := scaled(ticker.config.minPacedInterval); next < minInterval {
+		next = minInterval
+		handles.reportError(&PacingOverrunError{Elapsed: elapsed, Interval: interval})
+	}
+
+	now, err := handles.timer.Now()
+	if err != nil {
+		return
+	}
+	ticker.armAt(handles, now+max(next, immediateTickDelay), interval)
+}
@@ -0,0 +1,56 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestTickerWithCompletionPacing(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	work := make(chan time.Duration, 2)
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond,
+		hrtime.WithBackend(backend),
+		hrtime.WithTimestampSource(fakeTimestamps{backend}),
+		hrtime.WithCompletionPacing(time.Millisecond),
+		hrtime.WithHandler(func(uint64) {
+			backend.LastTimer().AdvanceTime(<-work)
+		}))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// The handler takes 3ms of its 10ms, so the next call starts 7ms after
+	// it completes.
+	timer := backend.LastTimer()
+	work <- 3 * time.Millisecond
+	timer.Fire()
+	timer.WaitForReader()
+	spec, flags := timer.LastSettime()
+	if flags != unix.TFD_TIMER_ABSTIME || time.Duration(spec.Value.Nano()) != 20*time.Millisecond {
+		t.Errorf("expected the timer to be armed for 7ms after the completion at 13ms, got %s (flags %d)", time.Duration(spec.Value.Nano()), flags)
+	}
+
+	// With 12ms of work, the minimum interval applies.
+	work <- 12 * time.Millisecond
+	timer.Fire()
+	timer.WaitForReader()
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Value.Nano()) != 33*time.Millisecond {
+		t.Errorf("expected the timer to be armed for 1ms after the completion at 32ms, got %s", time.Duration(spec.Value.Nano()))
+	}
+
+	select {
+	case err := <-ticker.Errors():
+		var overrun *hrtime.PacingOverrunError
+		if !errors.As(err, &overrun) || overrun.Elapsed != 12*time.Millisecond || overrun.Interval != 10*time.Millisecond {
+			t.Errorf("expected a PacingOverrunError for 12ms of a 10ms interval, got %v", err)
+		}
+	default:
+		t.Errorf("expected the overrun to be reported")
+	}
+}
@@ -0,0 +1,152 @@
+package hrtime
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// Pause disarms a running ticker.  No ticks are generated until Resume() is
+// called.  The channel remains open while the ticker is paused.
+func (ticker *Ticker[T]) Pause() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return fmt.Errorf("cannot Pause() a stopped ticker")
+	}
+	if ticker.inPausedState {
+		return fmt.Errorf("ticker is already paused")
+	}
+
+	if err := ticker.handles.timer.Settime(0, &unix.ItimerSpec{}); err != nil {
+		return err
+	}
+
+	ticker.inPausedState = true
+
+	return nil
+}
+
+// Resume re-arms a paused ticker.  The first tick after Resume() occurs one
+// interval after it is called.
+func (ticker *Ticker[T]) Resume() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return fmt.Errorf("cannot Resume() a stopped ticker")
+	}
+	if !ticker.inPausedState {
+		return fmt.Errorf("ticker is not paused")
+	}
+
+	if err := ticker.arm(ticker.handles, false, 0); err != nil {
+		return err
+	}
+
+	ticker.inPausedState = false
+
+	return nil
+}
+
+// pauseRun is used by the read loop to pause the ticker.  It returns false if
+// the ticker was not paused, because it is already paused, or because handles
+// no longer belong to the running ticker.
+func (ticker *Ticker[T]) pauseRun(handles *tickerHandles[T]) bool {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || ticker.inPausedState || ticker.handles != handles {
+		return false
+	}
+
+	if err := handles.timer.Settime(0, &unix.ItimerSpec{}); err != nil {
+		return false
+	}
+
+	ticker.inPausedState = true
+
+	return true
+}
+
+// resumeRun is used by ResumeAll() to resume the ticker.  It returns false if
+// the ticker was not resumed, because it is not paused, or because handles no
+// longer belong to the running ticker.
+func (ticker *Ticker[T]) resumeRun(handles *tickerHandles[T]) bool {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || !ticker.inPausedState || ticker.handles != handles {
+		return false
+	}
+
+	if err := ticker.arm(handles, false, 0); err != nil {
+		return false
+	}
+
+	ticker.inPausedState = false
+
+	return true
+}
+
+// PauseAll pauses every ticker in the process that is running, as if Pause()
+// had been called on each, for a maintenance window, say; ResumeAll() resumes
+// them.  Until then, Start() of any ticker starts it paused, as
+// StartPaused() would, so that tickers created during the window respect it
+// too, and ResumeAll() resumes them with the others.  Tickers that were
+// already paused, by Pause() or StartPaused(), are left as they are: they
+// are not resumed by ResumeAll().  A ticker can still be resumed individually
+// with Resume() while the window lasts.  Nothing about the tickers is changed
+// but whether their timers are armed, so they resume with their intervals,
+// rates, options and channels as they were.  Calling PauseAll() again pauses
+// nothing more.  Like Shutdown(), it applies to the tickers of this package,
+// but not to a ScheduleTicker.
+func PauseAll() {
+	activeRuns.mu.Lock()
+	activeRuns.pausedAll = true
+	runs := make([]*activeRun, 0, len(activeRuns.runs))
+	for _, run := range activeRuns.runs {
+		if !run.pausedByPauseAll {
+			runs = append(runs, run)
+		}
+	}
+	activeRuns.mu.Unlock()
+
+	for _, run := range runs {
+		if !run.pause() {
+			continue
+		}
+
+		// ResumeAll() may have been called since the list was taken.
+		activeRuns.mu.Lock()
+		stillPaused := activeRuns.pausedAll
+		run.pausedByPauseAll = stillPaused
+		activeRuns.mu.Unlock()
+		if !stillPaused {
+			run.resume()
+		}
+	}
+}
+
+// ResumeAll ends the window begun by PauseAll(): it resumes the tickers that
+// PauseAll() paused, or that were started during the window, as if Resume()
+// had been called on each, and Start() starts tickers running again.  Each
+// ticker's first tick is one interval after ResumeAll().  It does nothing if
+// PauseAll() is not in effect.
+func ResumeAll() {
+	activeRuns.mu.Lock()
+	activeRuns.pausedAll = false
+	var runs []*activeRun
+	for _, run := range activeRuns.runs {
+		if run.pausedByPauseAll {
+			run.pausedByPauseAll = false
+			runs = append(runs, run)
+		}
+	}
+	activeRuns.mu.Unlock()
+
+	for _, run := range runs {
+		run.resume()
+	}
+}
@@ -0,0 +1,163 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestMonotonicTickerPauseResume(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(5 * time.Millisecond)
+
+	if err := ticker.Pause(); err == nil {
+		t.Errorf("expected error on Pause() of stopped ticker")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	<-ticker.C
+
+	if err := ticker.Pause(); err != nil {
+		t.Fatalf("on Pause(): %s", err.Error())
+	}
+
+	select {
+	case <-ticker.C:
+		t.Errorf("received tick while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+	if err := ticker.Resume(); err == nil {
+		t.Errorf("expected error on Resume() of running ticker")
+	}
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after Resume()")
+	}
+}
+
+func TestMonotonicTickerOverrunPause(t *testing.T) {
+	paused := make(chan uint64, 1)
+	ticker := hrtime.NewMonotonicTicker(2*time.Millisecond, hrtime.WithOverrunPause(5, func(undelivered uint64) {
+		paused <- undelivered
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	var undelivered uint64
+	select {
+	case undelivered = <-paused:
+	case <-time.After(time.Second):
+		t.Fatalf("ticker did not pause on overrun")
+	}
+
+	if undelivered <= 5 {
+		t.Errorf("expected undelivered count > 5, got %d", undelivered)
+	}
+
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+
+	if ticks := <-ticker.C; ticks <= undelivered {
+		t.Errorf("expected first tick after Resume() to include %d undelivered ticks, got %d", undelivered, ticks)
+	}
+}
+
+func TestMonotonicTickerStartPaused(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(5 * time.Millisecond)
+
+	if err := ticker.StartPaused(); err != nil {
+		t.Fatalf("on StartPaused(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.Start(); err == nil {
+		t.Errorf("expected error on Start() after StartPaused()")
+	}
+
+	hrtime.WaitForReadLoop(ticker)
+	select {
+	case <-ticker.C:
+		t.Errorf("received tick before Resume()")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+
+	select {
+	case ticks := <-ticker.C:
+		if ticks != 1 {
+			t.Errorf("expected first tick after Resume() to have count 1, got %d", ticks)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after Resume()")
+	}
+}
+
+func TestPauseAll(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	running := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := running.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer running.Stop()
+	runningTimer := backend.LastTimer()
+
+	paused := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := paused.StartPaused(); err != nil {
+		t.Fatalf("on StartPaused(): %s", err.Error())
+	}
+	defer paused.Stop()
+	pausedTimer := backend.LastTimer()
+
+	hrtime.PauseAll()
+	defer hrtime.ResumeAll()
+	if runningTimer.Armed() {
+		t.Errorf("expected PauseAll() to disarm the running ticker")
+	}
+
+	// A ticker started during the window starts paused.
+	late := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := late.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer late.Stop()
+	lateTimer := backend.LastTimer()
+	if lateTimer.Armed() {
+		t.Errorf("expected a ticker started after PauseAll() to be disarmed")
+	}
+	if err := late.Pause(); err == nil {
+		t.Errorf("expected a ticker started after PauseAll() to be paused")
+	}
+
+	hrtime.ResumeAll()
+	if !runningTimer.Armed() || !lateTimer.Armed() {
+		t.Errorf("expected ResumeAll() to re-arm the tickers that PauseAll() paused")
+	}
+	if pausedTimer.Armed() {
+		t.Errorf("expected ResumeAll() to leave the ticker paused by StartPaused() paused")
+	}
+
+	// The tickers tick as before.
+	runningTimer.Advance(2)
+	runningTimer.WaitForReader()
+	if stats := running.Stats(); stats.Expirations != 2 {
+		t.Errorf("expected 2 expirations after ResumeAll(), got %d", stats.Expirations)
+	}
+}
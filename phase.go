@@ -0,0 +1,136 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A PhaseLockedGroup is a set of tickers with the same interval whose ticks
+// coincide.  Start() arms every ticker's timer with the same absolute first
+// expiration (on the tickers' shared clock), so the kernel expires them
+// together, and keeps them together, with no drift between them.  What
+// remains is the time each read goroutine takes to wake, which is normally a
+// few microseconds.
+//
+// Pausing, resuming or resetting one of the tickers individually re-arms it
+// relative to the time of the call, taking it out of phase with the group
+// until the group is started again.
+type PhaseLockedGroup struct {
+	// Tickers are the tickers of the group.
+	Tickers []*MonotonicTicker
+
+	interval time.Duration
+}
+
+// NewPhaseLockedGroup creates a group of n tickers that tick together at the
+// provided interval.  The opts are applied to every ticker.  Options that
+// change how the timer is armed (such as WithRate or WithImmediateFirstTick)
+// are ignored by the group's Start().
+func NewPhaseLockedGroup(interval time.Duration, n int, opts ...Option) *PhaseLockedGroup {
+	group := &PhaseLockedGroup{interval: interval}
+	for i := 0; i < n; i++ {
+		group.Tickers = append(group.Tickers, NewMonotonicTicker(interval, opts...))
+	}
+
+	return group
+}
+
+// Channels returns the channel of each ticker in the group, in order.  Each
+// Start() creates new channels, so Channels() should be called after Start().
+func (group *PhaseLockedGroup) Channels() []<-chan uint64 {
+	channels := make([]<-chan uint64, len(group.Tickers))
+	for i, ticker := range group.Tickers {
+		ticker.mu.Lock()
+		channels[i] = ticker.C
+		ticker.mu.Unlock()
+	}
+
+	return channels
+}
+
+// Start starts every ticker in the group, with their first ticks one
+// interval from now.  Like StartAll, it is all-or-nothing: if any ticker
+// fails to start, the others are stopped again.
+func (group *PhaseLockedGroup) Start() error {
+	if len(group.Tickers) == 0 {
+		return nil
+	}
+
+	for i, ticker := range group.Tickers {
+		if err := ticker.StartPaused(); err != nil {
+			return errors.Join(fmt.Errorf("on Start() of ticker %d: %w", i, err), StopAll(group.Tickers[:i]))
+		}
+	}
+
+	now, err := group.Tickers[0].timerNow()
+	if err != nil {
+		return errors.Join(err, StopAll(group.Tickers))
+	}
+
+	firstExpiration := now + scaled(group.interval)
+	for i, ticker := range group.Tickers {
+		if err := ticker.resumeAt(firstExpiration); err != nil {
+			return errors.Join(fmt.Errorf("on Start() of ticker %d: %w", i, err), StopAll(group.Tickers))
+		}
+	}
+
+	return nil
+}
+
+// Stop stops every ticker in the group, as StopAll does.
+func (group *PhaseLockedGroup) Stop() error {
+	return StopAll(group.Tickers)
+}
+
+// timerNow returns the current time on the clock of a started ticker's timer.
+func (ticker *Ticker[T]) timerNow() (time.Duration, error) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return 0, fmt.Errorf("ticker is stopped")
+	}
+
+	return ticker.handles.timer.Now()
+}
+
+// resumeAt resumes a paused ticker with its timer armed to first expire at
+// the absolute time firstExpiration on its clock, and every interval after
+// that.
+func (ticker *Ticker[T]) resumeAt(firstExpiration time.Duration) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || !ticker.inPausedState {
+		return fmt.Errorf("ticker is not paused")
+	}
+
+	if err := ticker.armAt(ticker.handles, firstExpiration, scaled(ticker.desiredInterval)); err != nil {
+		return err
+	}
+
+	ticker.inPausedState = false
+
+	return nil
+}
+
+// armAt arms (with the ticker lock held) the timer to first expire at the
+// absolute time firstExpiration on its clock, and every interval after that,
+// and resets the schedule.  If it fails, the timer is unchanged.
+func (ticker *Ticker[T]) armAt(handles *tickerHandles[T], firstExpiration time.Duration, interval time.Duration) error {
+	spec := periodicItimerSpec(interval)
+	spec.Value = unix.NsecToTimespec(int64(firstExpiration))
+	if err := handles.timer.Settime(ticker.config.absoluteArmFlags(handles.clock), spec); err != nil {
+		return err
+	}
+
+	handles.lastSentAt.Store(int64(ticker.config.timestamps.Now()))
+	handles.mu.Lock()
+	handles.schedule = tickSchedule{firstDeadline: firstExpiration, interval: interval}
+	handles.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package hrtime_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestPhaseLockedGroupArming(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	group := hrtime.NewPhaseLockedGroup(10*time.Millisecond, 3, hrtime.WithBackend(backend))
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer group.Stop()
+
+	timers := backend.Timers()
+	if len(timers) != 3 {
+		t.Fatalf("expected 3 timers, got %d", len(timers))
+	}
+
+	first, _ := timers[0].LastSettime()
+	for i, timer := range timers {
+		spec, flags := timer.LastSettime()
+		if flags != unix.TFD_TIMER_ABSTIME {
+			t.Errorf("expected timer %d to be armed with TFD_TIMER_ABSTIME", i)
+		}
+		if spec != first {
+			t.Errorf("expected timer %d to be armed like timer 0 (%+v), got %+v", i, first, spec)
+		}
+	}
+	if interval := time.Duration(first.Interval.Nano()); interval != 10*time.Millisecond {
+		t.Errorf("expected an interval of 10ms, got %s", interval)
+	}
+}
+
+func TestPhaseLockedGroupTicksTogether(t *testing.T) {
+	group := hrtime.NewPhaseLockedGroup(10*time.Millisecond, 4)
+
+	if err := group.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer group.Stop()
+
+	channels := group.Channels()
+	if len(channels) != 4 {
+		t.Fatalf("expected 4 channels, got %d", len(channels))
+	}
+
+	for round := 0; round < 5; round++ {
+		var wg sync.WaitGroup
+		for _, c := range channels {
+			wg.Add(1)
+			go func(c <-chan uint64) {
+				defer wg.Done()
+				<-c
+			}(c)
+		}
+		wg.Wait()
+
+		earliest, latest := group.Tickers[0].LastTickAt(), group.Tickers[0].LastTickAt()
+		for _, ticker := range group.Tickers[1:] {
+			at := ticker.LastTickAt()
+			earliest, latest = min(earliest, at), max(latest, at)
+		}
+
+		if spread := latest.Sub(earliest); spread > 2*time.Millisecond {
+			t.Errorf("on round %d, expected ticks within 2ms of each other, got a spread of %s", round, spread)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// WithPOSIXTimers makes the ticker use POSIX interval timers (timer_create(2)
+// with SIGEV_SIGNAL) in place of timerfd, for environments where timerfd is
+// unavailable or restricted (for instance, by a seccomp policy).
+//
+// A POSIX timer reports expirations by sending a signal to the process, so
+// this backend has costs that timerfd does not:
+//
+//   - Each timer needs a realtime signal of its own, because os/signal does
+//     not report which timer sent a signal.  Signals are taken from a pool
+//     (SIGRTMIN+3 to SIGRTMAX), so at most 30 tickers using this backend can
+//     be running at once; beyond that, Start() returns an error wrapping
+//     ErrNoTimerSignals.  The program must not otherwise use those signals.
+//   - Every expiration interrupts some thread of the process and passes
+//     through the Go runtime's signal handler, which is slower than waking a
+//     goroutine on a readable fd, and which can interrupt system calls made
+//     elsewhere in the program with EINTR.
+//   - Queued signals of the same number coalesce, so the number of
+//     expirations is computed from the timer's clock when a signal arrives,
+//     rather than reported by the kernel.  Relative arming is converted to
+//     absolute arming for the same reason, so on ClockRealtime a timer armed
+//     by a relative Settime follows steps of the clock.
+func WithPOSIXTimers() Option {
+	return func(config *tickerConfig) {
+		config.backend = posixTimerBackend{}
+	}
+}
+
+// ErrNoTimerSignals is returned (wrapped) by Start() when every realtime
+// signal available to the POSIX timer backend is in use.
+var ErrNoTimerSignals = errors.New("no realtime signal is available for a POSIX timer")
+
+// The realtime signals used by posixTimerBackend.  The first three realtime
+// signals are left alone, because the C library (and so cgo programs) uses
+// them internally.
+const (
+	firstTimerSignal = 35
+	lastTimerSignal  = 64
+)
+
+var timerSignals struct {
+	mu    sync.Mutex
+	inUse [lastTimerSignal - firstTimerSignal + 1]bool
+}
+
+func allocateTimerSignal() (syscall.Signal, error) {
+	timerSignals.mu.Lock()
+	defer timerSignals.mu.Unlock()
+
+	for i, inUse := range timerSignals.inUse {
+		if !inUse {
+			timerSignals.inUse[i] = true
+			return syscall.Signal(firstTimerSignal + i), nil
+		}
+	}
+
+	return 0, ErrNoTimerSignals
+}
+
+func releaseTimerSignal(sig syscall.Signal) {
+	timerSignals.mu.Lock()
+	timerSignals.inUse[int(sig)-firstTimerSignal] = false
+	timerSignals.mu.Unlock()
+}
+
+// sigevent is the kernel's struct sigevent, which is 64 bytes long.
+type sigevent struct {
+	value  uintptr
+	signo  int32
+	notify int32
+	_      [(64 - 8 - unsafe.Sizeof(uintptr(0))) / 4]int32
+}
+
+const sigevSignal = 0
+
+// posixTimerBackend is the Backend selected by WithPOSIXTimers.
+type posixTimerBackend struct{}
+
+func (posixTimerBackend) NewTimer(clock ClockID) (BackendTimer, error) {
+	sig, err := allocateTimerSignal()
+	if err != nil {
+		return nil, err
+	}
+
+	timer := &posixTimer{
+		clock:   clock,
+		sig:     sig,
+		signals: make(chan os.Signal, 1),
+		closed:  make(chan struct{}),
+	}
+	signal.Notify(timer.signals, sig)
+
+	event := sigevent{signo: int32(sig), notify: sigevSignal}
+	if _, _, errno := unix.Syscall(unix.SYS_TIMER_CREATE, uintptr(clock), uintptr(unsafe.Pointer(&event)), uintptr(unsafe.Pointer(&timer.id))); errno != 0 {
+		signal.Stop(timer.signals)
+		releaseTimerSignal(sig)
+		if errno == unix.EINVAL {
+			return nil, fmt.Errorf("%w (%s): %w", ErrUnsupportedClock, clock, errno)
+		}
+		return nil, errno
+	}
+
+	return timer, nil
+}
+
+// posixTimer is a BackendTimer for a POSIX timer.  deadline and interval
+// mirror the kernel's arming of the timer, and are used to count expirations
+// when a signal arrives.
+type posixTimer struct {
+	id      int32
+	clock   ClockID
+	sig     syscall.Signal
+	signals chan os.Signal
+	closed  chan struct{}
+
+	mu        sync.Mutex
+	deadline  time.Duration
+	interval  time.Duration
+	wasClosed bool
+}
+
+func (timer *posixTimer) Settime(flags int, spec *unix.ItimerSpec) error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.wasClosed {
+		return os.ErrClosed
+	}
+
+	absolute := *spec
+	if value := time.Duration(spec.Value.Nano()); value != 0 && flags&unix.TFD_TIMER_ABSTIME == 0 {
+		now, err := timer.Now()
+		if err != nil {
+			return err
+		}
+		absolute.Value = unix.NsecToTimespec(int64(addSaturating(now, value)))
+	}
+
+	if _, _, errno := unix.Syscall6(unix.SYS_TIMER_SETTIME, uintptr(timer.id), unix.TFD_TIMER_ABSTIME, uintptr(unsafe.Pointer(&absolute)), 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	timer.deadline = time.Duration(absolute.Value.Nano())
+	timer.interval = time.Duration(absolute.Interval.Nano())
+
+	return nil
+}
+
+func (timer *posixTimer) Gettime() (*unix.ItimerSpec, error) {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.wasClosed {
+		return nil, os.ErrClosed
+	}
+
+	spec := &unix.ItimerSpec{}
+	if _, _, errno := unix.Syscall(unix.SYS_TIMER_GETTIME, uintptr(timer.id), uintptr(unsafe.Pointer(spec)), 0); errno != 0 {
+		return nil, errno
+	}
+
+	return spec, nil
+}
+
+func (timer *posixTimer) Read() (uint64, error) {
+	for {
+		select {
+		case <-timer.signals:
+		case <-timer.closed:
+			return 0, os.ErrClosed
+		}
+
+		if expirations := timer.expirations(); expirations > 0 {
+			return expirations, nil
+		}
+	}
+}
+
+// expirations returns the number of expirations since the previous call, and
+// advances the mirrored deadline past them.  A signal can arrive with nothing
+// to report when it was sent before the timer was re-armed, or when its
+// expiration was already counted on the arrival of an earlier signal.
+func (timer *posixTimer) expirations() uint64 {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.deadline == 0 {
+		return 0
+	}
+
+	now, err := timer.Now()
+	if err != nil || now < timer.deadline {
+		return 0
+	}
+
+	if timer.interval == 0 {
+		timer.deadline = 0
+		return 1
+	}
+
+	expirations := uint64((now-timer.deadline)/timer.interval) + 1
+	timer.deadline += time.Duration(expirations) * timer.interval
+
+	return expirations
+}
+
+func (timer *posixTimer) ReadPending() (uint64, error) {
+	timer.mu.Lock()
+	closed := timer.wasClosed
+	timer.mu.Unlock()
+
+	if closed {
+		return 0, os.ErrClosed
+	}
+
+	return timer.expirations(), nil
+}
+
+func (timer *posixTimer) Now() (time.Duration, error) {
+	return clockNow(int32(timer.clock))
+}
+
+func (timer *posixTimer) Close() error {
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+
+	if timer.wasClosed {
+		return os.ErrClosed
+	}
+	timer.wasClosed = true
+
+	_, _, errno := unix.Syscall(unix.SYS_TIMER_DELETE, uintptr(timer.id), 0, 0)
+
+	signal.Stop(timer.signals)
+	releaseTimerSignal(timer.sig)
+	close(timer.closed)
+
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
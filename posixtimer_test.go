@@ -0,0 +1,83 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestMonotonicTickerWithPOSIXTimers(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(2*time.Millisecond, hrtime.WithPOSIXTimers())
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	ticks := uint64(0)
+	timeout := time.After(time.Second)
+	for ticks < 20 {
+		select {
+		case expirations := <-ticker.C:
+			ticks += expirations
+		case <-timeout:
+			t.Fatalf("expected 20 ticks within one second, got %d", ticks)
+		}
+	}
+
+	if err := ticker.Pause(); err != nil {
+		t.Fatalf("on Pause(): %s", err.Error())
+	}
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case <-ticker.C:
+		t.Errorf("received tick while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after Resume()")
+	}
+
+	ticker.Stop()
+	for range ticker.C {
+	}
+}
+
+func TestPOSIXTimersAreLimited(t *testing.T) {
+	var tickers []*hrtime.MonotonicTicker
+	defer func() {
+		for _, ticker := range tickers {
+			ticker.Stop()
+		}
+	}()
+
+	for i := 0; i < 30; i++ {
+		ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithPOSIXTimers())
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() of ticker %d: %s", i+1, err.Error())
+		}
+		tickers = append(tickers, ticker)
+	}
+
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithPOSIXTimers())
+	if err := ticker.Start(); !errors.Is(err, hrtime.ErrNoTimerSignals) {
+		t.Errorf("expected ErrNoTimerSignals on Start() of ticker 31, got %v", err)
+	}
+
+	tickers[0].Stop()
+	tickers = tickers[1:]
+	if err := ticker.Start(); err != nil {
+		t.Errorf("on Start() after a signal was released: %s", err.Error())
+	}
+	tickers = append(tickers, ticker)
+}
@@ -0,0 +1,37 @@
+package hrtime
+
+// WithDeliveryPredicate makes the ticker call allow before each delivery (a
+// send on its channel, or a call to its handler), and hold the delivery back
+// if it returns false, so that delivery can be gated (on a feature flag, say)
+// without stopping the ticker.  The ticks that are held back are accumulated,
+// as ticks that could not be sent are, and the first delivery that allow
+// lets through carries all of them.  No delivery is attempted for the ticks
+// held back, so they are not counted as Drops, and WithSkipMissed does not
+// discard them.
+//
+// Only the delivery is gated: the timer keeps running, and is read as
+// usual, so the Cumulative count, the lateness and the rest of Stats(),
+// heartbeats and the detection options are unaffected by allow.  The final
+// tick of a countdown (see WithCountdown) is delivered without consulting
+// allow.  allow is called from the read goroutine on every read, so it must
+// be cheap (an atomic load, for instance) and must not block.
+func WithDeliveryPredicate(allow func() bool) Option {
+	return func(config *tickerConfig) {
+		config.deliveryPredicate = allow
+	}
+}
+
+// deliveryAllowed reports whether the delivery predicate, if there is one,
+// lets the read loop deliver tick.
+func (ticker *Ticker[T]) deliveryAllowed(tick *tickInfo) bool {
+	allow := ticker.config.deliveryPredicate
+	if allow == nil {
+		return true
+	}
+
+	if count := ticker.config.countdown; count > 0 && tick.cumulative == count {
+		return true
+	}
+
+	return allow()
+}
@@ -0,0 +1,50 @@
+package hrtime_test
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerWithDeliveryPredicate(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	var enabled atomic.Bool
+	var calls []uint64
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond,
+		hrtime.WithBackend(backend),
+		hrtime.WithDeliveryPredicate(enabled.Load),
+		hrtime.WithHandler(func(expirations uint64) {
+			calls = append(calls, expirations)
+		}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	for i := 0; i < 3; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	if len(calls) != 0 {
+		t.Fatalf("expected no deliveries while the predicate is false, got %v", calls)
+	}
+	if stats := ticker.Stats(); stats.Expirations != 3 || stats.Drops != 0 {
+		t.Errorf("expected 3 expirations read and no drops while held back, got %d and %d", stats.Expirations, stats.Drops)
+	}
+
+	enabled.Store(true)
+	for i := 0; i < 2; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	if expected := []uint64{4, 1}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected deliveries of %v ticks, got %v", expected, calls)
+	}
+}
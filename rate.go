@@ -0,0 +1,158 @@
+package hrtime
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// IntervalForRate returns the interval between ticks at perSecond ticks per
+// second, rounded to the nearest nanosecond.  When one second is not an
+// exact multiple of that interval, a ticker using it drifts from the
+// requested rate; see WithRate for a ticker whose long-run rate is exact.
+func IntervalForRate(perSecond float64) time.Duration {
+	return time.Duration(math.Round(float64(time.Second) / perSecond))
+}
+
+// NewTickerHz creates a MonotonicTicker that ticks hz times per second.  Its
+// interval is the nearest whole number of nanoseconds to 1/hz seconds (as
+// returned by IntervalForRate), and it uses WithRate, so that its long-run
+// frequency is hz even when the interval is inexact.  It returns an error if
+// hz is not a positive, finite number, if it is below the one micro-tick per
+// second resolution of WithRate, or if its period is shorter than the
+// resolution of the ticker's clock (as reported by clock_getres(2)).
+func NewTickerHz(hz float64, opts ...Option) (*MonotonicTicker, error) {
+	if !(hz > 0) || math.IsInf(hz, 1) {
+		return nil, fmt.Errorf("frequency must be a positive, finite number of Hz, got %g", hz)
+	}
+	if math.Round(hz*microTicksPerTick) == 0 {
+		return nil, fmt.Errorf("frequency of %g Hz is below the resolution of %g Hz", hz, 1.0/microTicksPerTick)
+	}
+
+	clock := newTickerConfig(opts).clock
+	resolution, err := clockResolution(clock)
+	if err != nil {
+		return nil, err
+	}
+
+	if period := float64(time.Second) / hz; period < float64(max(resolution, 1)) {
+		return nil, fmt.Errorf("frequency of %g Hz has a period shorter than the %s resolution of %s", hz, resolution, clock)
+	}
+
+	return NewMonotonicTicker(IntervalForRate(hz), withOptions(opts, WithRate(hz))...), nil
+}
+
+// Rates used by WithRate are kept in micro-ticks per second.
+const microTicksPerTick = 1_000_000
+
+// WithRate makes the ticker tick perSecond times per second on average, in
+// place of the interval passed to the constructor.  Rather than arming a
+// periodic timer, the read goroutine re-arms a one-shot timer after each
+// tick for the absolute time at which the next tick is due.  Tick k is due
+// k/perSecond seconds after the ticker is armed, rounded down to the
+// nanosecond, so when the ideal interval is not a whole number of
+// nanoseconds the actual intervals vary slightly, while the long-run rate
+// stays exact and does not drift.
+// The rate is kept to a resolution of one micro-tick per second.
+//
+// If the read goroutine falls behind, tick deadlines that have passed are
+// counted as expirations, as they would be by a periodic timer.  Reset() and
+// BoostInterval() return the ticker to plain periodic scheduling.
+func WithRate(perSecond float64) Option {
+	return func(config *tickerConfig) {
+		config.rate = uint64(math.Round(perSecond * microTicksPerTick))
+	}
+}
+
+// deadline returns the nominal time of expiration k (counting from 1) since
+// the timer was armed.
+func (schedule *tickSchedule) deadline(k uint64) time.Duration {
+	if schedule.rate == 0 {
+		return schedule.firstDeadline + time.Duration(k-1)*schedule.interval
+	}
+
+	// k seconds, in nanoseconds, divided by the rate in micro-ticks/second
+	hi, lo := bits.Mul64(k, uint64(time.Second)*microTicksPerTick)
+	offset, _ := bits.Div64(hi, lo, schedule.rate)
+
+	return schedule.epoch + time.Duration(offset)
+}
+
+// firstPendingDeadline returns the index of the first rate-scheduled
+// deadline that is after now.
+func (schedule *tickSchedule) firstPendingDeadline(now time.Duration) uint64 {
+	if now < schedule.epoch {
+		return 1
+	}
+
+	hi, lo := bits.Mul64(uint64(now-schedule.epoch), schedule.rate)
+	passed, _ := bits.Div64(hi, lo, uint64(time.Second)*microTicksPerTick)
+
+	return passed + 1
+}
+
+// rateSchedule returns the schedule of a rate-scheduled ticker armed at
+// armedAt.  If immediate is true, the schedule is shifted so that the first
+// tick is due right away.
+func (ticker *Ticker[T]) rateSchedule(armedAt time.Duration, immediate bool) tickSchedule {
+	schedule := tickSchedule{interval: scaled(ticker.desiredInterval), epoch: armedAt, rate: scaledRate(ticker.rate)}
+	if immediate {
+		schedule.epoch = 0
+		schedule.epoch = armedAt + immediateTickDelay - schedule.deadline(1)
+	}
+	schedule.firstDeadline = schedule.deadline(1)
+
+	return schedule
+}
+
+// rearmRate is called by the read loop after each read of expirations.  If
+// the ticker is rate-scheduled, it re-arms the timer for the next deadline
+// that has not passed, and returns the number of deadlines that passed
+// without being reported by the timer (because the loop was late to re-arm
+// it), and whether it re-armed the timer.
+func (ticker *Ticker[T]) rearmRate(handles *tickerHandles[T], expirations uint64) (missed uint64, rearmed bool) {
+	handles.mu.Lock()
+	rateScheduled := handles.schedule.rate != 0
+	handles.mu.Unlock()
+
+	if !rateScheduled {
+		return 0, false
+	}
+
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || ticker.inPausedState || ticker.handles != handles {
+		return 0, false
+	}
+
+	now, err := handles.timer.Now()
+	if err != nil {
+		return 0, false
+	}
+
+	handles.mu.Lock()
+	schedule := &handles.schedule
+	next := schedule.expirationsSinceArm + expirations + 1
+	if pending := schedule.firstPendingDeadline(now); pending > next {
+		missed = pending - next
+		next = pending
+	}
+	deadline := schedule.deadline(next)
+	handles.mu.Unlock()
+
+	if err := handles.timer.Settime(ticker.config.absoluteArmFlags(handles.clock), oneShotItimerSpec(deadline)); err != nil {
+		return missed, false
+	}
+
+	return missed, true
+}
+
+// oneShotItimerSpec returns the ItimerSpec for a timer that expires once, at
+// the absolute time deadline.
+func oneShotItimerSpec(deadline time.Duration) *unix.ItimerSpec {
+	return &unix.ItimerSpec{Value: unix.NsecToTimespec(int64(deadline))}
+}
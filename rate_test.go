@@ -0,0 +1,155 @@
+package hrtime_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestIntervalForRate(t *testing.T) {
+	for _, testCase := range []struct {
+		perSecond float64
+		expected  time.Duration
+	}{
+		{1000, time.Millisecond},
+		{95, 10526316 * time.Nanosecond},
+		{3, 333333333 * time.Nanosecond},
+		{0.5, 2 * time.Second},
+	} {
+		if interval := hrtime.IntervalForRate(testCase.perSecond); interval != testCase.expected {
+			t.Errorf("for %g/s, expected interval %s, got %s", testCase.perSecond, testCase.expected, interval)
+		}
+	}
+}
+
+func TestTickerWithRate(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	ticks := uint64(0)
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithBackend(backend), hrtime.WithRate(95), hrtime.WithHandler(func(expirations uint64) {
+		ticks += expirations
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.WaitForReader()
+
+	for now, _ := timer.Now(); now < time.Second; now, _ = timer.Now() {
+		if !timer.Armed() {
+			t.Fatalf("timer was not re-armed after %d ticks", ticks)
+		}
+		timer.Fire()
+		timer.WaitForReader()
+	}
+
+	if ticks != 95 {
+		t.Errorf("expected 95 ticks in one second, got %d", ticks)
+	}
+	if stats := ticker.Stats(); stats.ReArms != stats.Expirations || stats.ReArms != 95 {
+		t.Errorf("expected one re-arm per expiration, got %d re-arms for %d expirations", stats.ReArms, stats.Expirations)
+	}
+
+	// The first deadline is at 1s + 10.526316ms.  When the loop is late to
+	// re-arm, the deadlines it missed are counted.
+	timer.AdvanceTime(100 * time.Millisecond)
+	timer.WaitForReader()
+
+	if ticks != 95+9 {
+		t.Errorf("expected 104 ticks in 1.1 seconds, got %d", ticks)
+	}
+	if stats := ticker.Stats(); stats.ReArms != 96 || stats.Expirations != 104 {
+		t.Errorf("expected the missed deadlines to be counted without re-arms, got %d re-arms for %d expirations", stats.ReArms, stats.Expirations)
+	}
+
+	if err := ticker.Reset(10 * time.Millisecond); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Interval.Nano()) != 10*time.Millisecond {
+		t.Errorf("expected Reset() to arm a periodic timer, got interval %s", time.Duration(spec.Interval.Nano()))
+	}
+}
+
+func TestTickerWithRateAndImmediateFirstTick(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithBackend(backend), hrtime.WithRate(3), hrtime.WithImmediateFirstTick())
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	spec, _ := backend.LastTimer().LastSettime()
+	if deadline := time.Duration(spec.Value.Nano()); deadline != time.Nanosecond {
+		t.Errorf("expected first deadline at 1ns, got %s", deadline)
+	}
+}
+
+func TestTickerWithRateOnClock(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithRate(95))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	ticks := uint64(0)
+	oneSecond := time.After(time.Second + 5*time.Millisecond)
+	for counting := true; counting; {
+		select {
+		case expirations := <-ticker.C:
+			ticks += expirations
+		case <-oneSecond:
+			counting = false
+		}
+	}
+
+	if ticks < 94 || ticks > 95 {
+		t.Errorf("expected 95 ticks in one second, got %d", ticks)
+	}
+}
+
+func TestNewTickerHz(t *testing.T) {
+	const hz = 300
+	ticker, err := hrtime.NewTickerHz(hz)
+	if err != nil {
+		t.Fatalf("on NewTickerHz(): %s", err.Error())
+	}
+	if interval := ticker.Config().Interval; interval != 3333333*time.Nanosecond {
+		t.Errorf("expected an interval of 3.333333ms, got %s", interval)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	ticks := uint64(0)
+	stopAt := time.After(time.Second)
+	for receiving := true; receiving; {
+		select {
+		case expirations := <-ticker.C:
+			ticks += expirations
+		case <-stopAt:
+			receiving = false
+		}
+	}
+	ticker.Stop()
+
+	if ticks < hz*9/10 || ticks > hz*11/10 {
+		t.Errorf("expected about %d ticks in one second, got %d", hz, ticks)
+	}
+}
+
+func TestNewTickerHzRejectsFrequency(t *testing.T) {
+	for _, hz := range []float64{0, -1, math.NaN(), math.Inf(1), 1e-7, 2e9} {
+		if _, err := hrtime.NewTickerHz(hz); err == nil {
+			t.Errorf("expected NewTickerHz(%g) to fail", hz)
+		}
+	}
+}
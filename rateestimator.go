@@ -0,0 +1,91 @@
+package hrtime
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// A RateEstimator turns the coalesced tick counts delivered by a ticker
+// into a smoothed estimate of events per second.  Each observation gives a
+// count and the time at which it was taken (such as the Delta and FiredAt
+// of a RichTick); the count is taken to cover the span since the previous
+// observation.  The estimate is an exponentially weighted moving average,
+// weighted by time rather than by observation, so that a span of 10ms
+// carries ten times the weight of a span of 1ms whatever the counts are.
+// A RateEstimator is safe for concurrent use.
+type RateEstimator struct {
+	halfLife time.Duration
+
+	mu           sync.Mutex
+	lastObserved MonotonicTime
+	hasObserved  bool
+	hasEstimate  bool
+	estimate     float64
+}
+
+// NewRateEstimator creates a RateEstimator whose estimate gives half of its
+// weight to the most recent halfLife of observations.  A shorter halfLife
+// follows changes in the rate more quickly, and a longer one is steadier.
+// A halfLife of 0 or less makes the estimate the rate over the most recent
+// span alone.
+func NewRateEstimator(halfLife time.Duration) *RateEstimator {
+	return &RateEstimator{halfLife: halfLife}
+}
+
+// Observe adds an observation of count events at the time at.  The first
+// observation after creation or Reset() only marks the start of the first
+// span, because the span its count covers is unknown.  An observation that
+// is not later than the previous one is ignored.
+func (estimator *RateEstimator) Observe(count uint64, at MonotonicTime) {
+	estimator.mu.Lock()
+	defer estimator.mu.Unlock()
+
+	if !estimator.hasObserved {
+		estimator.lastObserved = at
+		estimator.hasObserved = true
+		return
+	}
+
+	span := at.Sub(estimator.lastObserved)
+	if span <= 0 {
+		return
+	}
+	estimator.lastObserved = at
+
+	rate := float64(count) / span.Seconds()
+	if !estimator.hasEstimate || estimator.halfLife <= 0 {
+		estimator.estimate = rate
+		estimator.hasEstimate = true
+		return
+	}
+
+	weight := 1 - math.Exp2(-float64(span)/float64(estimator.halfLife))
+	estimator.estimate += weight * (rate - estimator.estimate)
+}
+
+// ObserveTick adds an observation of the Delta ticks reported by tick, at
+// its FiredAt time.
+func (estimator *RateEstimator) ObserveTick(tick RichTick) {
+	estimator.Observe(tick.Delta, tick.FiredAt)
+}
+
+// Rate returns the estimated number of events per second.  It returns 0
+// until two observations have been made.
+func (estimator *RateEstimator) Rate() float64 {
+	estimator.mu.Lock()
+	defer estimator.mu.Unlock()
+
+	return estimator.estimate
+}
+
+// Reset discards all observations, as if the estimator had just been
+// created.
+func (estimator *RateEstimator) Reset() {
+	estimator.mu.Lock()
+	defer estimator.mu.Unlock()
+
+	estimator.hasObserved = false
+	estimator.hasEstimate = false
+	estimator.estimate = 0
+}
@@ -0,0 +1,67 @@
+package hrtime_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestRateEstimator(t *testing.T) {
+	estimator := hrtime.NewRateEstimator(100 * time.Millisecond)
+	at := hrtime.MonotonicTime(time.Second)
+
+	estimator.Observe(5, at)
+	if rate := estimator.Rate(); rate != 0 {
+		t.Errorf("expected a rate of 0 after one observation, got %f", rate)
+	}
+
+	// 10 events every 10ms is 1000/s, whether they arrive one at a time or
+	// coalesced.
+	for i := 0; i < 10; i++ {
+		at = at.Add(10 * time.Millisecond)
+		estimator.Observe(10, at)
+	}
+	if rate := estimator.Rate(); math.Abs(rate-1000) > 1e-6 {
+		t.Errorf("expected a rate of 1000/s, got %f", rate)
+	}
+
+	at = at.Add(30 * time.Millisecond)
+	estimator.ObserveTick(hrtime.RichTick{Delta: 30, FiredAt: at})
+	if rate := estimator.Rate(); math.Abs(rate-1000) > 1e-6 {
+		t.Errorf("expected a coalesced observation at the same rate to keep it at 1000/s, got %f", rate)
+	}
+
+	// After one half-life at 2000/s, the estimate is halfway there.
+	at = at.Add(100 * time.Millisecond)
+	estimator.Observe(200, at)
+	if rate := estimator.Rate(); math.Abs(rate-1500) > 1e-6 {
+		t.Errorf("expected a rate of 1500/s after one half-life at 2000/s, got %f", rate)
+	}
+
+	estimator.Observe(1000, at)
+	if rate := estimator.Rate(); math.Abs(rate-1500) > 1e-6 {
+		t.Errorf("expected an observation with no elapsed time to be ignored, got %f", rate)
+	}
+
+	estimator.Reset()
+	if rate := estimator.Rate(); rate != 0 {
+		t.Errorf("expected a rate of 0 after Reset(), got %f", rate)
+	}
+	estimator.Observe(1, at.Add(time.Second))
+	estimator.Observe(4, at.Add(time.Second+time.Millisecond))
+	if rate := estimator.Rate(); math.Abs(rate-4000) > 1e-6 {
+		t.Errorf("expected the first span after Reset() to set the rate, got %f", rate)
+	}
+}
+
+func TestRateEstimatorWithoutSmoothing(t *testing.T) {
+	estimator := hrtime.NewRateEstimator(0)
+	estimator.Observe(0, 0)
+	estimator.Observe(10, hrtime.MonotonicTime(time.Second))
+	estimator.Observe(50, hrtime.MonotonicTime(2*time.Second))
+	if rate := estimator.Rate(); rate != 50 {
+		t.Errorf("expected the rate over the last span alone, got %f", rate)
+	}
+}
@@ -0,0 +1,63 @@
+package hrtime
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// An ExpirationReader reads a MonotonicTicker as a stream of bytes, for code
+// that consumes byte streams: each value that the ticker delivers is read as
+// its count, 8 bytes in little-endian order.  (A timerfd is read the same
+// way, but in host byte order.)
+type ExpirationReader struct {
+	ticker  *MonotonicTicker
+	pending []byte
+	buffer  [8]byte
+}
+
+// NewExpirationReader creates an ExpirationReader that reads the ticks of
+// ticker.  The ticker is read from its channel, so nothing else should
+// receive from the channel while the reader is in use.
+func NewExpirationReader(ticker *MonotonicTicker) *ExpirationReader {
+	return &ExpirationReader{ticker: ticker}
+}
+
+// Read blocks until the ticker delivers a value, and reads its 8-byte count
+// into p.  If p is shorter than 8 bytes, the rest of the count is returned by
+// the following Reads, which do not block.  Read reads at most one count,
+// even if p has room for more.  It returns io.EOF once the ticker has
+// stopped, or if it has never been started; a ticker that is started again
+// can be read again.
+func (reader *ExpirationReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if len(reader.pending) == 0 {
+		reader.ticker.mu.Lock()
+		c := reader.ticker.C
+		reader.ticker.mu.Unlock()
+
+		if c == nil {
+			return 0, io.EOF
+		}
+
+		expirations, isOpen := <-c
+		if !isOpen {
+			return 0, io.EOF
+		}
+
+		binary.LittleEndian.PutUint64(reader.buffer[:], expirations)
+		reader.pending = reader.buffer[:]
+	}
+
+	n := copy(p, reader.pending)
+	reader.pending = reader.pending[n:]
+
+	return n, nil
+}
+
+// Close stops the ticker, so that ExpirationReader is an io.ReadCloser.
+func (reader *ExpirationReader) Close() error {
+	return reader.ticker.Stop()
+}
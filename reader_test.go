@@ -0,0 +1,74 @@
+package hrtime_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestExpirationReader(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+
+	var reader io.ReadCloser = hrtime.NewExpirationReader(ticker)
+	if _, err := reader.Read(make([]byte, 8)); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF on Read() of a ticker that was never started, got %v", err)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	timer := backend.LastTimer()
+	done := make(chan struct{})
+	firing := make(chan struct{})
+	go func() {
+		// Ticks are only sent to a ready receiver, so keep firing until
+		// the reader is done.
+		defer close(firing)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				timer.Advance(3)
+			}
+		}
+	}()
+
+	b := make([]byte, 8)
+	if n, err := io.ReadFull(reader, b); err != nil || n != 8 {
+		t.Fatalf("on Read(): %d, %v", n, err)
+	}
+	if count := binary.LittleEndian.Uint64(b); count == 0 || count%3 != 0 {
+		t.Errorf("expected a count of whole advances of 3, got %d", count)
+	}
+
+	// A short buffer gets the count in pieces.
+	short := make([]byte, 3)
+	var pieces []byte
+	for len(pieces) < 8 {
+		n, err := reader.Read(short)
+		if err != nil {
+			t.Fatalf("on short Read(): %s", err.Error())
+		}
+		pieces = append(pieces, short[:n]...)
+	}
+	if len(pieces) != 8 || binary.LittleEndian.Uint64(pieces)%3 != 0 {
+		t.Errorf("expected an 8-byte count in pieces, got %v", pieces)
+	}
+
+	close(done)
+	<-firing
+	if err := reader.Close(); err != nil {
+		t.Fatalf("on Close(): %s", err.Error())
+	}
+	if _, err := reader.Read(b); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF on Read() after Close(), got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package hrtime
+
+import "time"
+
+// The number of intervals kept by RecentIntervals() by default.
+const defaultRecentIntervals = 64
+
+// WithRecentIntervals sets the number of observed intervals between ticks
+// that RecentIntervals() keeps, 64 by default.  A count of 0 or less
+// disables it.
+func WithRecentIntervals(n int) Option {
+	return func(config *tickerConfig) {
+		config.recentIntervals = n
+	}
+}
+
+// RecentIntervals returns the most recent observed intervals between the
+// ticker's timer reads, oldest first: each is the time from one read to the
+// next, according to the ticker's TimestampSource, so it shows the timing
+// that the read goroutine saw, including any time spent paused.  At most the
+// number set by WithRecentIntervals are kept; fewer are returned early in a
+// run.  The intervals are those of the current run, or of the last one if the
+// ticker is stopped, and nil is returned if the ticker has never been
+// started.  The returned slice is a copy, so it can be kept and modified
+// without affecting the ticker.
+func (ticker *Ticker[T]) RecentIntervals() []time.Duration {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return nil
+	}
+
+	handles.mu.Lock()
+	defer handles.mu.Unlock()
+
+	return handles.recentIntervals.snapshot()
+}
+
+// A durationRing keeps the most recent intervals between the times that it
+// is given.  It is protected by the handles lock.
+type durationRing struct {
+	slots    []time.Duration
+	next     int
+	full     bool
+	previous MonotonicTime
+}
+
+func newDurationRing(size int) durationRing {
+	if size <= 0 {
+		return durationRing{}
+	}
+
+	return durationRing{slots: make([]time.Duration, size)}
+}
+
+// record records the interval from the previous time recorded to at.
+func (ring *durationRing) record(at MonotonicTime) {
+	if len(ring.slots) == 0 {
+		return
+	}
+
+	if ring.previous != 0 {
+		ring.slots[ring.next] = at.Sub(ring.previous)
+		if ring.next++; ring.next == len(ring.slots) {
+			ring.next, ring.full = 0, true
+		}
+	}
+	ring.previous = at
+}
+
+// snapshot returns a copy of the intervals, oldest first.
+func (ring *durationRing) snapshot() []time.Duration {
+	if !ring.full {
+		return append([]time.Duration{}, ring.slots[:ring.next]...)
+	}
+
+	return append(append(make([]time.Duration, 0, len(ring.slots)), ring.slots[ring.next:]...), ring.slots[:ring.next]...)
+}
@@ -0,0 +1,54 @@
+package hrtime_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerRecentIntervals(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond,
+		hrtime.WithBackend(backend),
+		hrtime.WithTimestampSource(fakeTimestamps{backend}),
+		hrtime.WithRecentIntervals(3),
+		hrtime.WithHandler(func(uint64) {}))
+
+	if intervals := ticker.RecentIntervals(); intervals != nil {
+		t.Errorf("expected no intervals before Start(), got %v", intervals)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	for _, n := range []uint64{1, 2} {
+		timer.Advance(n)
+		timer.WaitForReader()
+	}
+	if intervals := ticker.RecentIntervals(); !reflect.DeepEqual(intervals, []time.Duration{2 * time.Millisecond}) {
+		t.Errorf("expected one interval of 2ms, got %v", intervals)
+	}
+
+	// Only the 3 most recent intervals are kept.
+	for _, n := range []uint64{1, 1, 3} {
+		timer.Advance(n)
+		timer.WaitForReader()
+	}
+	expected := []time.Duration{time.Millisecond, time.Millisecond, 3 * time.Millisecond}
+	intervals := ticker.RecentIntervals()
+	if !reflect.DeepEqual(intervals, expected) {
+		t.Errorf("expected intervals %v, got %v", expected, intervals)
+	}
+
+	// The intervals returned are a copy.
+	intervals[0] = 0
+	if intervals := ticker.RecentIntervals(); !reflect.DeepEqual(intervals, expected) {
+		t.Errorf("expected modifying the returned intervals not to affect the ticker, got %v", intervals)
+	}
+}
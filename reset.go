@@ -0,0 +1,223 @@
+package hrtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reset changes the ticker's interval.  If the ticker is running, its timer
+// is re-armed, so the next tick occurs one new interval after Reset() is
+// called.  If the ticker is paused or stopped, the new interval takes effect
+// on Resume() or Start().  Reset() cancels any BoostInterval() in progress,
+// and ends rate scheduling (see WithRate).
+//
+// If the timer cannot be re-armed, Reset() returns the error and changes
+// nothing: the timer keeps its previous arming (timerfd_settime(2) does not
+// modify a timer when it fails), so the ticker keeps ticking at its previous
+// interval, and any boost in progress still ends as scheduled.
+func (ticker *Ticker[T]) Reset(interval time.Duration) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if err := ticker.resetInterval(interval); err != nil {
+		return err
+	}
+
+	ticker.cancelBoost()
+
+	return nil
+}
+
+// ResetAligned changes the interval of a running ticker and realigns it in
+// one step: the timer is re-armed, with an absolute expiration, for the next
+// whole multiple of interval on the ticker's clock, and every interval after
+// that.  On ClockRealtime, for instance, ResetAligned(500*time.Millisecond)
+// makes the ticker tick on each half second of the wall clock.  Unlike a
+// Reset() followed by realigning the ticker, it never ticks at the new
+// interval out of alignment, nor at the old interval after the call.
+//
+// Alignment is only meaningful on a clock whose epoch is shared, so
+// ResetAligned returns an error unless the ticker uses ClockRealtime or
+// ClockTAI.  It also returns an error if the ticker is stopped or paused, or
+// if the timer cannot be re-armed, in which case the ticker is unchanged.
+// Like Reset(), it cancels any BoostInterval() in progress, and ends rate
+// scheduling (see WithRate).  A later Reset(), Resume() or Start() arms the
+// timer relative to its call again, unaligned.
+func (ticker *Ticker[T]) ResetAligned(interval time.Duration) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if interval <= 0 {
+		return fmt.Errorf("cannot ResetAligned() to a non-positive interval")
+	}
+	if clock := ticker.config.clock; clock != ClockRealtime && clock != ClockTAI {
+		return fmt.Errorf("cannot ResetAligned() a ticker on %s; it must use %s or %s", clock, ClockRealtime, ClockTAI)
+	}
+	if ticker.inStoppedState || ticker.inPausedState {
+		return fmt.Errorf("cannot ResetAligned() a ticker that is not running")
+	}
+
+	handles := ticker.handles
+	now, err := handles.timer.Now()
+	if err != nil {
+		return err
+	}
+
+	period := scaled(interval)
+	if err := ticker.armAt(handles, (now/period+1)*period, period); err != nil {
+		return err
+	}
+
+	ticker.desiredInterval = interval
+	ticker.rate = 0
+	ticker.cancelBoost()
+
+	return nil
+}
+
+// resetInterval must be called with the ticker lock held.  If re-arming the
+// timer fails, the ticker's interval and rate are left unchanged.
+func (ticker *Ticker[T]) resetInterval(interval time.Duration) error {
+	return ticker.resetSchedule(interval, 0)
+}
+
+// resetSchedule is resetInterval, with a rate (in micro-ticks per second, as
+// for WithRate) in place of the interval if rate is not 0.
+func (ticker *Ticker[T]) resetSchedule(interval time.Duration, rate uint64) error {
+	previousInterval, previousRate := ticker.desiredInterval, ticker.rate
+	ticker.desiredInterval = interval
+	ticker.rate = rate
+
+	if ticker.inStoppedState || ticker.inPausedState {
+		return nil
+	}
+
+	if err := ticker.arm(ticker.handles, false, 0); err != nil {
+		ticker.desiredInterval, ticker.rate = previousInterval, previousRate
+		return err
+	}
+
+	return nil
+}
+
+// ResetClock changes the kernel clock that drives the ticker.  If the ticker
+// is running or paused, this is heavier than Reset(): a new timer is created
+// on clock, armed (unless the ticker is paused) as Reset() would arm it, and
+// swapped in for the old one, which is then closed.  The channel and the
+// tick counts carry on across the change, but expirations of the old timer
+// that had not yet been read are lost.  If the ticker is stopped, the clock
+// is used from the next Start().  If the kernel does not support timers on
+// clock, ResetClock() returns an error wrapping ErrUnsupportedClock, and the
+// ticker keeps using its current clock.
+func (ticker *Ticker[T]) ResetClock(clock ClockID) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	timer, err := ticker.config.backend.NewTimer(clock)
+	if err != nil {
+		return err
+	}
+
+	if ticker.inStoppedState {
+		timer.Close()
+		ticker.config.clock = clock
+		return nil
+	}
+
+	handles := ticker.handles
+	handles.mu.Lock()
+	if handles.areClosed {
+		handles.mu.Unlock()
+		timer.Close()
+		return fmt.Errorf("cannot ResetClock() of a ticker whose timer has failed")
+	}
+	oldTimer, oldClock := handles.timer, handles.clock
+	handles.timer, handles.clock = timer, clock
+	handles.mu.Unlock()
+
+	if !ticker.inPausedState {
+		if err := ticker.arm(handles, false, 0); err != nil {
+			handles.mu.Lock()
+			handles.timer, handles.clock = oldTimer, oldClock
+			handles.mu.Unlock()
+			timer.Close()
+			return err
+		}
+	}
+
+	ticker.config.clock = clock
+	oldTimer.Close()
+
+	return nil
+}
+
+// BoostInterval temporarily changes the interval of a running ticker to
+// fast, then reverts it after duration d (which is measured from the call).
+// The revert restores the interval that was in effect before the first of
+// any overlapping boosts (and, for a ticker with WithRate, its rate); if
+// BoostInterval() is called again while a boost is in progress, the latest
+// call's fast interval and duration win.  Reset() and Stop() cancel the
+// pending revert.  If the timer cannot be re-armed for the fast interval,
+// BoostInterval() returns the error, and changes nothing: a boost in progress
+// still ends as scheduled.  If it cannot be re-armed for the revert, the error
+// is reported on Errors(), and the revert is tried again after another d.
+func (ticker *Ticker[T]) BoostInterval(fast time.Duration, d time.Duration) error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return fmt.Errorf("cannot BoostInterval() a stopped ticker")
+	}
+
+	restoreInterval, restoreRate := ticker.desiredInterval, ticker.rate
+	if ticker.boostTimer != nil {
+		restoreInterval, restoreRate = ticker.boostRestoreInterval, ticker.boostRestoreRate
+	}
+
+	if err := ticker.resetInterval(fast); err != nil {
+		return err
+	}
+
+	ticker.cancelBoost()
+	ticker.boostRestoreInterval, ticker.boostRestoreRate = restoreInterval, restoreRate
+	ticker.scheduleBoostEnd(d)
+
+	return nil
+}
+
+// scheduleBoostEnd must be called with the ticker lock held.  It schedules
+// the revert of the boost in progress after d.
+func (ticker *Ticker[T]) scheduleBoostEnd(d time.Duration) {
+	ticker.boostGeneration++
+	generation := ticker.boostGeneration
+	ticker.boostTimer = time.AfterFunc(scaled(d), func() {
+		ticker.endBoost(generation, d)
+	})
+}
+
+// endBoost restores the pre-boost interval and rate, unless the boost
+// identified by generation has been superseded or cancelled.  If the timer
+// cannot be re-armed, the error is reported, and the revert is tried again
+// after retryAfter.
+func (ticker *Ticker[T]) endBoost(generation uint64, retryAfter time.Duration) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.boostTimer == nil || ticker.boostGeneration != generation {
+		return
+	}
+
+	ticker.boostTimer = nil
+	if err := ticker.resetSchedule(ticker.boostRestoreInterval, ticker.boostRestoreRate); err != nil {
+		ticker.handles.reportError(fmt.Errorf("on ending BoostInterval(): %w", err))
+		ticker.scheduleBoostEnd(retryAfter)
+	}
+}
+
+// cancelBoost must be called with the ticker lock held.
+func (ticker *Ticker[T]) cancelBoost() {
+	if ticker.boostTimer != nil {
+		ticker.boostTimer.Stop()
+		ticker.boostTimer = nil
+	}
+}
@@ -0,0 +1,362 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestMonotonicTickerReset(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Hour)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.Reset(5 * time.Millisecond); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after Reset()")
+	}
+}
+
+func TestMonotonicTickerBoostInterval(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(40 * time.Millisecond)
+
+	if err := ticker.BoostInterval(time.Millisecond, time.Millisecond); err == nil {
+		t.Errorf("expected error on BoostInterval() of stopped ticker")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.BoostInterval(time.Millisecond, time.Hour); err != nil {
+		t.Fatalf("on first BoostInterval(): %s", err.Error())
+	}
+	if err := ticker.BoostInterval(2*time.Millisecond, 60*time.Millisecond); err != nil {
+		t.Fatalf("on second BoostInterval(): %s", err.Error())
+	}
+
+	boostedTicks := uint64(0)
+	boostEnds := time.After(50 * time.Millisecond)
+	for counting := true; counting; {
+		select {
+		case ticks := <-ticker.C:
+			boostedTicks += ticks
+		case <-boostEnds:
+			counting = false
+		}
+	}
+
+	if boostedTicks < 15 {
+		t.Errorf("expected at least 15 ticks during boost, got %d", boostedTicks)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	<-ticker.C
+	afterBoost := time.Now()
+	<-ticker.C
+	if interval := time.Since(afterBoost); interval < 30*time.Millisecond {
+		t.Errorf("expected interval to revert to 40ms after boost, measured %s", interval)
+	}
+}
+
+func TestMonotonicTickerResetSettimeFailure(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	ticks := uint64(0)
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(expirations uint64) {
+		ticks += expirations
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	injected := errors.New("injected settime failure")
+	timer.FailNextSettime(injected)
+
+	if err := ticker.Reset(time.Millisecond); !errors.Is(err, injected) {
+		t.Errorf("expected Reset() to return the injected error, got %v", err)
+	}
+
+	if !timer.Armed() {
+		t.Fatalf("expected timer to remain armed after failed Reset()")
+	}
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Interval.Nano()) != 10*time.Millisecond {
+		t.Errorf("expected timer to keep its 10ms interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+
+	timer.AdvanceTime(30 * time.Millisecond)
+	timer.WaitForReader()
+	if ticks != 3 {
+		t.Errorf("expected 3 ticks in 30ms after failed Reset(), got %d", ticks)
+	}
+
+	// The failed Reset() does not change the interval armed by Resume().
+	if err := ticker.Pause(); err != nil {
+		t.Fatalf("on Pause(): %s", err.Error())
+	}
+	if err := ticker.Resume(); err != nil {
+		t.Fatalf("on Resume(): %s", err.Error())
+	}
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Interval.Nano()) != 10*time.Millisecond {
+		t.Errorf("expected Resume() to arm the 10ms interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+
+	if err := ticker.Reset(time.Millisecond); err != nil {
+		t.Fatalf("on second Reset(): %s", err.Error())
+	}
+	if spec, _ := timer.LastSettime(); time.Duration(spec.Interval.Nano()) != time.Millisecond {
+		t.Errorf("expected second Reset() to arm a 1ms interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+}
+
+func TestTickerResetClock(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+
+	ticks := uint64(0)
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(expirations uint64) {
+		ticks += expirations
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	oldTimer := backend.LastTimer()
+	oldTimer.Advance(2)
+	oldTimer.WaitForReader()
+
+	if err := ticker.ResetClock(hrtime.ClockBoottime); err != nil {
+		t.Fatalf("on ResetClock(): %s", err.Error())
+	}
+
+	newTimer := backend.LastTimer()
+	if newTimer == oldTimer || newTimer.Clock() != hrtime.ClockBoottime {
+		t.Fatalf("expected ResetClock() to create a timer on CLOCK_BOOTTIME")
+	}
+	if _, err := oldTimer.Gettime(); err == nil {
+		t.Errorf("expected the old timer to be closed")
+	}
+	if !newTimer.Armed() {
+		t.Errorf("expected the new timer to be armed")
+	}
+
+	newTimer.Advance(3)
+	newTimer.WaitForReader()
+	if ticks != 5 {
+		t.Errorf("expected 5 ticks across the clock change, got %d", ticks)
+	}
+
+	select {
+	case _, open := <-ticker.C:
+		if !open {
+			t.Errorf("expected channel to stay open across the clock change")
+		}
+	default:
+	}
+}
+
+func TestTickerResetClockUnsupported(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+
+	if err := ticker.ResetClock(hrtime.ClockID(99)); !errors.Is(err, hrtime.ErrUnsupportedClock) {
+		t.Errorf("expected ErrUnsupportedClock from ResetClock() of a stopped ticker, got %v", err)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.ResetClock(hrtime.ClockID(99)); !errors.Is(err, hrtime.ErrUnsupportedClock) {
+		t.Errorf("expected ErrUnsupportedClock from ResetClock() of a running ticker, got %v", err)
+	}
+
+	if err := ticker.ResetClock(hrtime.ClockBoottime); err != nil {
+		t.Fatalf("on ResetClock(): %s", err.Error())
+	}
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Errorf("no tick received after ResetClock()")
+	}
+}
+
+func TestMonotonicTickerResetAligned(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithClock(hrtime.ClockRealtime))
+
+	if err := ticker.ResetAligned(500 * time.Millisecond); err == nil {
+		t.Errorf("expected error on ResetAligned() of a stopped ticker")
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.AdvanceTime(1300 * time.Millisecond)
+	timer.WaitForReader()
+
+	if err := ticker.ResetAligned(500 * time.Millisecond); err != nil {
+		t.Fatalf("on ResetAligned(): %s", err.Error())
+	}
+
+	spec, flags := timer.LastSettime()
+	if flags&unix.TFD_TIMER_ABSTIME == 0 {
+		t.Errorf("expected ResetAligned() to arm the timer with TFD_TIMER_ABSTIME")
+	}
+	if value := time.Duration(spec.Value.Nano()); value != 1500*time.Millisecond {
+		t.Errorf("expected the first aligned expiration at 1.5s, got %s", value)
+	}
+	if interval := time.Duration(spec.Interval.Nano()); interval != 500*time.Millisecond {
+		t.Errorf("expected the timer to be armed with a 500ms interval, got %s", interval)
+	}
+	if interval := ticker.Config().Interval; interval != 500*time.Millisecond {
+		t.Errorf("expected the ticker's interval to be 500ms, got %s", interval)
+	}
+
+	timer.FailNextSettime(unix.EINVAL)
+	if err := ticker.ResetAligned(time.Second); err == nil {
+		t.Errorf("expected ResetAligned() to return the error of re-arming the timer")
+	}
+	if interval := ticker.Config().Interval; interval != 500*time.Millisecond {
+		t.Errorf("expected a failed ResetAligned() to leave the interval at 500ms, got %s", interval)
+	}
+}
+
+func TestMonotonicTickerResetAlignedRequiresRealtimeClock(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Second)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.ResetAligned(500 * time.Millisecond); err == nil {
+		t.Errorf("expected error on ResetAligned() of a CLOCK_MONOTONIC ticker")
+	}
+}
+
+func TestMonotonicTickerResetAlignedTicksOnBoundaries(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	ticker := hrtime.NewMonotonicTicker(time.Hour, hrtime.WithClock(hrtime.ClockRealtime))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if err := ticker.ResetAligned(interval); err != nil {
+		t.Fatalf("on ResetAligned(): %s", err.Error())
+	}
+
+	select {
+	case <-ticker.C:
+		if offset := time.Duration(time.Now().UnixNano()) % interval; offset > 5*time.Millisecond {
+			t.Errorf("expected the tick close to a %s boundary, got %s after it", interval, offset)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no tick received after ResetAligned()")
+	}
+}
+
+// waitForSettime waits until the most recent arming of timer satisfies done.
+func waitForSettime(t *testing.T, timer *hrtimetest.Timer, what string, done func(spec unix.ItimerSpec, flags int) bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !done(timer.LastSettime()); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s", what)
+		}
+	}
+}
+
+func TestBoostIntervalFailureKeepsEarlierBoost(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	if err := ticker.BoostInterval(time.Millisecond, 50*time.Millisecond); err != nil {
+		t.Fatalf("on BoostInterval(): %s", err.Error())
+	}
+	failure := errors.New("failure")
+	timer.FailNextSettime(failure)
+	if err := ticker.BoostInterval(2*time.Millisecond, time.Hour); !errors.Is(err, failure) {
+		t.Fatalf("expected the failed re-arming to be returned, got %v", err)
+	}
+
+	waitForSettime(t, timer, "the first boost to end as scheduled", func(spec unix.ItimerSpec, _ int) bool {
+		return spec.Interval.Nano() == int64(time.Second)
+	})
+}
+
+func TestBoostIntervalRestoresRate(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(100*time.Millisecond, hrtime.WithBackend(backend), hrtime.WithRate(10))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	if err := ticker.BoostInterval(time.Millisecond, 20*time.Millisecond); err != nil {
+		t.Fatalf("on BoostInterval(): %s", err.Error())
+	}
+	if rate := ticker.Config().Rate; rate != 0 {
+		t.Errorf("expected no rate during the boost, got %g", rate)
+	}
+
+	waitForSettime(t, timer, "the rate schedule to be restored", func(_ unix.ItimerSpec, flags int) bool {
+		return flags&unix.TFD_TIMER_ABSTIME != 0
+	})
+	if rate := ticker.Config().Rate; rate != 10 {
+		t.Errorf("expected the rate of 10 to be restored, got %g", rate)
+	}
+}
+
+func TestBoostIntervalRetriesFailedRevert(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	if err := ticker.BoostInterval(time.Millisecond, 20*time.Millisecond); err != nil {
+		t.Fatalf("on BoostInterval(): %s", err.Error())
+	}
+	failure := errors.New("failure")
+	timer.FailNextSettime(failure)
+
+	select {
+	case err := <-ticker.Errors():
+		if !errors.Is(err, failure) {
+			t.Errorf("expected the failed revert to be reported, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the failed revert to be reported")
+	}
+	waitForSettime(t, timer, "the revert to be retried", func(spec unix.ItimerSpec, _ int) bool {
+		return spec.Interval.Nano() == int64(time.Second)
+	})
+}
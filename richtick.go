@@ -0,0 +1,50 @@
+package hrtime
+
+import "time"
+
+// A RichTick describes the ticks delivered by a RichTicker.
+type RichTick struct {
+	// Delta is the number of ticks that have occurred since the last
+	// channel read.  This is the value delivered by a MonotonicTicker.
+	Delta uint64
+
+	// Cumulative is the number of ticks that have occurred since the
+	// ticker was started, including ticks not yet delivered.
+	Cumulative uint64
+
+	// FiredAt is the time, according to the ticker's TimestampSource, at
+	// which the read goroutine observed the most recent tick.
+	FiredAt MonotonicTime
+
+	// Lateness is how long after its nominal time (measured on the
+	// ticker's clock) the most recent tick was observed.  The nominal
+	// times are whole intervals after the timer was last armed, by
+	// Start() or Resume().
+	Lateness time.Duration
+
+	// Seq is the sequence number of this delivery.  It is 1 for the first
+	// value delivered after Start() and increases by exactly one with each
+	// value delivered, so, unlike Delta (which counts coalesced ticks), a
+	// gap in Seq means that the consumer missed a delivered value, for
+	// instance while handing off after SwapChannel().  It starts again at
+	// 1 on each Start().
+	Seq uint64
+}
+
+// A RichTicker is a ticker that delivers a RichTick for each channel read.
+type RichTicker = Ticker[RichTick]
+
+// NewRichTicker creates a ticker that is intended to fire a tick near every
+// interval and that delivers RichTick values rather than plain tick counts.
+// It accepts the same options as NewMonotonicTicker.
+func NewRichTicker(interval time.Duration, opts ...Option) *RichTicker {
+	return newTicker(interval, opts, func(tick tickInfo) RichTick {
+		return RichTick{
+			Delta:      tick.delta,
+			Cumulative: tick.cumulative,
+			FiredAt:    tick.firedAt,
+			Lateness:   tick.lateness,
+			Seq:        tick.sequence,
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestRichTicker(t *testing.T) {
+	ticker := hrtime.NewRichTicker(10 * time.Millisecond)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	first := <-ticker.C
+	if first.Delta != 1 || first.Cumulative != 1 {
+		t.Errorf("on first read, expected Delta = 1 and Cumulative = 1, got %d and %d", first.Delta, first.Cumulative)
+	}
+	if first.Lateness < 0 || first.Lateness > 10*time.Millisecond {
+		t.Errorf("on first read, expected Lateness to be between 0 and 10ms, got %s", first.Lateness)
+	}
+
+	time.Sleep(35 * time.Millisecond)
+
+	second := <-ticker.C
+	if second.Delta < 3 {
+		t.Errorf("on read after 35ms sleep, expected Delta >= 3, got %d", second.Delta)
+	}
+	if second.Cumulative != first.Cumulative+second.Delta {
+		t.Errorf("on read after 35ms sleep, expected Cumulative = %d, got %d", first.Cumulative+second.Delta, second.Cumulative)
+	}
+	if elapsed := second.FiredAt.Sub(first.FiredAt); elapsed < 30*time.Millisecond {
+		t.Errorf("expected FiredAt values at least 30ms apart, got %s", elapsed)
+	}
+}
+
+func TestRichTickerSequence(t *testing.T) {
+	ticker := hrtime.NewRichTicker(2 * time.Millisecond)
+
+	for round := 0; round < 2; round++ {
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start() in round %d: %s", round, err.Error())
+		}
+
+		for expectedSeq := uint64(1); expectedSeq <= 5; expectedSeq++ {
+			if expectedSeq == 3 {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if tick := <-ticker.C; tick.Seq != expectedSeq {
+				t.Errorf("in round %d, expected Seq = %d, got %d", round, expectedSeq, tick.Seq)
+			}
+		}
+
+		ticker.Stop()
+	}
+}
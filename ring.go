@@ -0,0 +1,101 @@
+package hrtime
+
+import "sync/atomic"
+
+// WithRingBuffer makes the ticker deliver its ticks into a preallocated ring
+// buffer of size records, which the consumer polls with TryNext(), instead of
+// sending them on its channel.  Writing a record costs a few atomic
+// operations and never fails, so at very high tick rates it is cheaper than a
+// channel send, and no ticks are coalesced for want of a ready receiver: each
+// record describes the ticks read since the previous record.  The channel is
+// still created by Start() and closed when the ticker stops, but no values
+// are sent on it.
+//
+// The ring buffer is single-producer, single-consumer: the read goroutine is
+// its only producer, and TryNext() must only be called from one goroutine at
+// a time.  When the ring is full, a new record overwrites the oldest one,
+// whose ticks are lost; the number of records overwritten is reported by
+// RingOverwrites().  The ring is created with the ticker and kept across
+// Stop() and Start(), so records written before a Stop() can still be read
+// after it.  A size of 0 or less disables it.  It does not apply to a ticker
+// with a handler (see WithHandler).
+func WithRingBuffer(size int) Option {
+	return func(config *tickerConfig) {
+		config.ringSize = size
+	}
+}
+
+// TryNext returns the oldest record in the ticker's ring buffer (see
+// WithRingBuffer), and true, or false if the ring is empty or the ticker does
+// not use one.  It never blocks.
+func (ticker *Ticker[T]) TryNext() (tick T, ok bool) {
+	if ticker.ring == nil {
+		return tick, false
+	}
+
+	return ticker.ring.pop()
+}
+
+// RingOverwrites returns the number of records of the ticker's ring buffer
+// (see WithRingBuffer) that were overwritten, because the ring was full,
+// before TryNext() could return them.
+func (ticker *Ticker[T]) RingOverwrites() uint64 {
+	if ticker.ring == nil {
+		return 0
+	}
+
+	return ticker.ring.overwritten.Load()
+}
+
+// A tickRing is a single-producer, single-consumer ring buffer that
+// overwrites its oldest record when it is full.  head counts the records
+// pushed and tail the records popped or overwritten, so the records
+// available are those from tail to head.  Both the producer (to discard the
+// oldest record) and the consumer advance tail, with a compare-and-swap, so
+// a consumer whose record is overwritten while it copies it notices, because
+// its swap fails, and retries with the next record.
+type tickRing[T any] struct {
+	slots       []T
+	head        atomic.Uint64
+	tail        atomic.Uint64
+	overwritten atomic.Uint64
+}
+
+func newTickRing[T any](size int) *tickRing[T] {
+	if size <= 0 {
+		return nil
+	}
+
+	return &tickRing[T]{slots: make([]T, size)}
+}
+
+// push is only called by the producer.
+func (ring *tickRing[T]) push(value T) {
+	size := uint64(len(ring.slots))
+	head := ring.head.Load()
+	if tail := ring.tail.Load(); head-tail >= size {
+		// If the swap fails, the consumer has just made room.
+		if ring.tail.CompareAndSwap(tail, tail+1) {
+			ring.overwritten.Add(1)
+		}
+	}
+
+	ring.slots[head%size] = value
+	ring.head.Store(head + 1)
+}
+
+// pop is only called by the consumer.
+func (ring *tickRing[T]) pop() (value T, ok bool) {
+	size := uint64(len(ring.slots))
+	for {
+		tail := ring.tail.Load()
+		if tail == ring.head.Load() {
+			return value, false
+		}
+
+		value = ring.slots[tail%size]
+		if ring.tail.CompareAndSwap(tail, tail+1) {
+			return value, true
+		}
+	}
+}
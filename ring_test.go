@@ -0,0 +1,102 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerWithRingBuffer(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithRingBuffer(4))
+
+	if _, ok := ticker.TryNext(); ok {
+		t.Errorf("expected TryNext() of an empty ring to return false")
+	}
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// Nothing polls the ring, so the six records overwrite the first two.
+	timer := backend.LastTimer()
+	for advance := uint64(1); advance <= 6; advance++ {
+		timer.Advance(advance)
+		timer.WaitForReader()
+	}
+
+	for expected := uint64(3); expected <= 6; expected++ {
+		if delta, ok := ticker.TryNext(); !ok || delta != expected {
+			t.Errorf("expected a record of %d ticks, got %d, %t", expected, delta, ok)
+		}
+	}
+	if delta, ok := ticker.TryNext(); ok {
+		t.Errorf("expected the ring to be empty, got a record of %d ticks", delta)
+	}
+	if overwrites := ticker.RingOverwrites(); overwrites != 2 {
+		t.Errorf("expected 2 overwritten records, got %d", overwrites)
+	}
+
+	select {
+	case value := <-ticker.C:
+		t.Errorf("expected nothing to be sent on the channel, got %d", value)
+	default:
+	}
+}
+
+func TestTickerWithoutRingBuffer(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	<-ticker.C
+	if _, ok := ticker.TryNext(); ok {
+		t.Errorf("expected TryNext() of a ticker without a ring buffer to return false")
+	}
+}
+
+func TestRingBufferConcurrentPoll(t *testing.T) {
+	ticker := hrtime.NewRichTicker(50*time.Microsecond, hrtime.WithRingBuffer(8))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	// The consumer polls as the read goroutine writes, and is sometimes
+	// lapped by it; the records it gets must still be in order.
+	var lastSeq, received uint64
+	for deadline := time.Now().Add(200 * time.Millisecond); time.Now().Before(deadline); {
+		tick, ok := ticker.TryNext()
+		if !ok {
+			if received%16 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			continue
+		}
+		if tick.Seq <= lastSeq {
+			t.Fatalf("expected records in order, got Seq %d after %d", tick.Seq, lastSeq)
+		}
+		lastSeq = tick.Seq
+		received++
+	}
+	ticker.Stop()
+
+	for {
+		tick, ok := ticker.TryNext()
+		if !ok {
+			break
+		}
+		lastSeq = tick.Seq
+		received++
+	}
+
+	if received == 0 {
+		t.Fatalf("expected records from the ring buffer")
+	}
+	if received+ticker.RingOverwrites() != lastSeq {
+		t.Errorf("expected each of the %d records to be received or overwritten, got %d received and %d overwritten", lastSeq, received, ticker.RingOverwrites())
+	}
+}
@@ -0,0 +1,101 @@
+package hrtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunEvery calls f at every interval, until f returns an error or ctx is
+// done, and returns that error (or ctx.Err()).  It blocks while it runs,
+// using a MonotonicTicker created with opts, which it stops before it
+// returns.  f is called once for each value delivered on the ticker's
+// channel: if f takes longer than the interval, the ticks that pass in the
+// meantime are coalesced, and lead to a single further call rather than one
+// call for each tick.  Calls are never concurrent.
+func RunEvery(ctx context.Context, interval time.Duration, f func() error, opts ...Option) error {
+	ticker, err := NewMonotonicTickerWithContext(ctx, interval, opts...)
+	if err != nil {
+		return err
+	}
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := f(); err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+// A BudgetExceededError reports that a handler of BudgetedRun returned after
+// the deadline of its budget.
+type BudgetExceededError struct {
+	// Elapsed is the time from the nominal time of the tick to the return
+	// of the handler, and Budget the time that the handler was allowed.
+	Elapsed time.Duration
+	Budget  time.Duration
+
+	// Err is the error that the handler returned, if any (typically
+	// context.DeadlineExceeded, from a handler that honored its context).
+	Err error
+}
+
+func (err *BudgetExceededError) Error() string {
+	if err.Err != nil {
+		return fmt.Sprintf("tick handler took %s of its %s budget: %s", err.Elapsed, err.Budget, err.Err)
+	}
+	return fmt.Sprintf("tick handler took %s of its %s budget", err.Elapsed, err.Budget)
+}
+
+func (err *BudgetExceededError) Unwrap() error {
+	return err.Err
+}
+
+// BudgetedRun is like RunEvery, but each call of handler is given a budget of
+// fraction of the interval, so that the work for a tick leaves headroom
+// before the next: handler receives a context, derived from ctx, whose
+// deadline is the budget after the nominal time of the tick (when the timer
+// expired for it, as opposed to when the tick was received).  If handler
+// returns after that deadline, BudgetedRun stops, and returns a
+// *BudgetExceededError, which wraps the error that handler returned, if
+// any; an error returned within the budget is returned as it is.  fraction
+// must be positive; with a fraction of 1 or more, the budget runs into the
+// next tick.  The ticker is a RichTicker created with opts.  The budget is a
+// fraction of the interval as SetTimeScale() scales it, so that it keeps the
+// same headroom before the next tick.
+//
+// The deadline only cancels the context: BudgetedRun cannot interrupt a
+// handler that ignores its context, so such a handler runs for as long as it
+// takes, the ticks that pass meanwhile are coalesced (as with RunEvery), and
+// the overrun is only reported once the handler returns.
+func BudgetedRun(ctx context.Context, interval time.Duration, fraction float64, handler func(context.Context) error, opts ...Option) error {
+	if !(fraction > 0) {
+		return fmt.Errorf("budget fraction must be positive, got %g", fraction)
+	}
+	budget := time.Duration(fraction * float64(scaled(interval)))
+
+	ticker := NewRichTicker(interval, withOptions(opts, WithBaseContext(ctx))...)
+	if err := ticker.Start(); err != nil {
+		return err
+	}
+	defer ticker.Stop()
+
+	for tick := range ticker.C {
+		nominal := tick.FiredAt.Add(-tick.Lateness)
+		deadline := timeOfMonotonic(nominal).Add(budget)
+		tickCtx, cancel := context.WithDeadline(ctx, deadline)
+		err := handler(tickCtx)
+		cancel()
+
+		if elapsed := MonotonicNow().Sub(nominal); elapsed > budget {
+			return &BudgetExceededError{Elapsed: elapsed, Budget: budget, Err: err}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,128 @@
+package hrtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestRunEveryStopsOnError(t *testing.T) {
+	failure := errors.New("failure")
+	calls := 0
+	err := hrtime.RunEvery(context.Background(), time.Millisecond, func() error {
+		calls++
+		if calls == 5 {
+			return failure
+		}
+		return nil
+	})
+
+	if !errors.Is(err, failure) {
+		t.Errorf("expected RunEvery() to return the function's error, got %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("expected 5 calls, got %d", calls)
+	}
+}
+
+func TestRunEveryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := hrtime.RunEvery(ctx, 5*time.Millisecond, func() error {
+		calls++
+		return nil
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected RunEvery() to return context.DeadlineExceeded, got %v", err)
+	}
+	if calls == 0 || calls > 6 {
+		t.Errorf("expected about 6 calls in 30ms at 5ms, got %d", calls)
+	}
+}
+
+func TestRunEveryCoalescesSlowCalls(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	hrtime.RunEvery(ctx, time.Millisecond, func() error {
+		calls++
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	if calls > 6 {
+		t.Errorf("expected slow calls to coalesce ticks into about 5 calls, got %d", calls)
+	}
+}
+
+func TestBudgetedRun(t *testing.T) {
+	failure := errors.New("failure")
+	calls := 0
+	err := hrtime.BudgetedRun(context.Background(), 10*time.Millisecond, 0.5, func(ctx context.Context) error {
+		deadline, hasDeadline := ctx.Deadline()
+		if !hasDeadline || time.Until(deadline) > 5*time.Millisecond {
+			t.Errorf("expected a deadline within the 5ms budget, got %v (%t)", time.Until(deadline), hasDeadline)
+		}
+		if calls++; calls == 3 {
+			return failure
+		}
+		return nil
+	})
+
+	if !errors.Is(err, failure) {
+		t.Errorf("expected BudgetedRun() to return the handler's error, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestBudgetedRunReportsOverrun(t *testing.T) {
+	calls := 0
+	err := hrtime.BudgetedRun(context.Background(), 10*time.Millisecond, 0.2, func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var exceeded *hrtime.BudgetExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected a *BudgetExceededError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || exceeded.Budget != 2*time.Millisecond || exceeded.Elapsed < exceeded.Budget {
+		t.Errorf("expected the 2ms budget to be exceeded with context.DeadlineExceeded, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the run to stop after the first call, got %d calls", calls)
+	}
+
+	if err := hrtime.BudgetedRun(context.Background(), time.Millisecond, 0, nil); err == nil {
+		t.Errorf("expected a fraction of 0 to be rejected")
+	}
+}
+
+func TestBudgetedRunUnderTimeScale(t *testing.T) {
+	defer hrtime.SetTimeScale(1)
+	hrtime.SetTimeScale(0.01)
+
+	// The budget is half of the scaled interval of 10ms, not of the second.
+	err := hrtime.BudgetedRun(context.Background(), time.Second, 0.5, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var exceeded *hrtime.BudgetExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected a *BudgetExceededError, got %v", err)
+	}
+	if exceeded.Budget != 5*time.Millisecond {
+		t.Errorf("expected a budget of 5ms under a time scale of 0.01, got %s", exceeded.Budget)
+	}
+}
@@ -0,0 +1,133 @@
+package hrtime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A ScheduleTicker fires at a fixed list of offsets from the time at which it
+// is started, rather than at a fixed interval.
+type ScheduleTicker struct {
+	// After the ticker is started, the index (into the offsets provided to
+	// NewScheduleTicker) of each offset is written to this channel when
+	// that offset is reached.  The channel is closed after the last index
+	// is delivered, or when the ticker is stopped.
+	C              chan int
+	offsets        []time.Duration
+	mu             sync.Mutex
+	tickFile       *os.File
+	stop           chan struct{}
+	inStoppedState bool
+}
+
+// NewScheduleTicker creates a ticker that fires once at each of the offsets,
+// which are measured from the time at which Start() is called.  The offsets
+// must not be negative, and must be strictly increasing.
+func NewScheduleTicker(offsets []time.Duration) (*ScheduleTicker, error) {
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("at least one offset is required")
+	}
+
+	for i, offset := range offsets {
+		if offset < 0 {
+			return nil, fmt.Errorf("offset %d (%s) is negative", i, offset)
+		}
+		if i > 0 && offset <= offsets[i-1] {
+			return nil, fmt.Errorf("offset %d (%s) is not greater than the previous offset (%s)", i, offset, offsets[i-1])
+		}
+	}
+
+	return &ScheduleTicker{
+		offsets:        append([]time.Duration(nil), offsets...),
+		inStoppedState: true,
+	}, nil
+}
+
+// Start starts the schedule.  Each offset is converted to an absolute time on
+// CLOCK_MONOTONIC relative to the moment Start() is called, so lateness in
+// delivering one index does not delay the ones that follow.  Each index is
+// delivered with a blocking send, so no index is skipped: if the receiver is
+// slow, later indices whose times have already passed are delivered as soon
+// as it is ready.  Each time Start() is run, ticker.C is replaced with a new
+// channel, and the schedule begins again from the first offset.  Once the
+// schedule is exhausted, Stop() must still be called before Start() can be
+// run again.
+func (ticker *ScheduleTicker) Start() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if !ticker.inStoppedState {
+		return fmt.Errorf("must Stop() before performing Start() again")
+	}
+
+	timerFile, err := timerfdCreate(ClockMonotonic)
+	if err != nil {
+		return err
+	}
+
+	startedAt, err := clockNow(unix.CLOCK_MONOTONIC)
+	if err != nil {
+		timerFile.Close()
+		return err
+	}
+
+	ticker.C = make(chan int)
+	ticker.tickFile = timerFile
+	ticker.stop = make(chan struct{})
+	ticker.inStoppedState = false
+
+	go ticker.run(timerFile, ticker.C, ticker.stop, startedAt)
+
+	return nil
+}
+
+func (ticker *ScheduleTicker) run(timerFile *os.File, c chan int, stop chan struct{}, startedAt time.Duration) {
+	defer close(c)
+	defer timerFile.Close()
+
+	// Scaling can round distinct offsets to the same time, which still
+	// fires each index in turn.
+	scaledOffsets := make([]time.Duration, len(ticker.offsets))
+	for i, offset := range ticker.offsets {
+		scaledOffsets[i] = scaled(offset)
+	}
+
+	b := make([]byte, 8)
+	for i := range ticker.offsets {
+		deadline := &unix.ItimerSpec{Value: unix.NsecToTimespec(int64(startedAt + scaledOffsets[i]))}
+		if err := settimeUsingFile(timerFile, unix.TFD_TIMER_ABSTIME, deadline); err != nil {
+			return
+		}
+
+		if bytesRead, err := timerFile.Read(b); bytesRead != 8 || err != nil {
+			return
+		}
+
+		select {
+		case c <- i:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop stops the schedule, if it is running.  The associated channel will be
+// closed from the ticker side.
+func (ticker *ScheduleTicker) Stop() error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return nil
+	}
+
+	close(ticker.stop)
+	ticker.tickFile.Close()
+	ticker.inStoppedState = true
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestScheduleTicker(t *testing.T) {
+	if _, err := hrtime.NewScheduleTicker([]time.Duration{10 * time.Millisecond, 10 * time.Millisecond}); err == nil {
+		t.Errorf("expected error on NewScheduleTicker() with offsets that are not strictly increasing")
+	}
+	if _, err := hrtime.NewScheduleTicker(nil); err == nil {
+		t.Errorf("expected error on NewScheduleTicker() with no offsets")
+	}
+
+	offsets := []time.Duration{10 * time.Millisecond, 30 * time.Millisecond, 60 * time.Millisecond}
+	ticker, err := hrtime.NewScheduleTicker(offsets)
+	if err != nil {
+		t.Fatalf("on NewScheduleTicker(): %s", err.Error())
+	}
+
+	startedAt := time.Now()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	expectedIndex := 0
+	for index := range ticker.C {
+		elapsed := time.Since(startedAt)
+		if index != expectedIndex {
+			t.Errorf("expected index %d, got %d", expectedIndex, index)
+		}
+		if elapsed < offsets[index] || elapsed > offsets[index]+10*time.Millisecond {
+			t.Errorf("expected index %d to be delivered at %s, delivered at %s", index, offsets[index], elapsed)
+		}
+		expectedIndex++
+	}
+
+	if expectedIndex != len(offsets) {
+		t.Errorf("expected %d indices before channel closed, got %d", len(offsets), expectedIndex)
+	}
+}
@@ -0,0 +1,182 @@
+package hrtime
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// FD returns the file descriptor number of the ticker's timerfd, which
+// identifies the timer in the results of SelectReady().  The descriptor
+// belongs to the ticker: it must not be read (except with
+// WithExternalDriven), closed or re-armed by the caller, and it changes on
+// each Start() (and on ResetClock()).  Once the ticker stops, the number can
+// be reused for an unrelated file, so it should not be kept across a Stop().
+// FD returns an error if the ticker is stopped, or does not use the timerfd
+// backend.  It is Linux-only, like the rest of the package.
+func (ticker *Ticker[T]) FD() (int, error) {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState {
+		return -1, fmt.Errorf("cannot get FD() of a stopped ticker")
+	}
+
+	timer, _ := ticker.handles.currentTimer()
+	timerfd, isTimerfd := timer.(*timerfdTimer)
+	if !isTimerfd {
+		return -1, fmt.Errorf("ticker does not use a timerfd")
+	}
+
+	// os.File.Fd() would put the file into blocking mode, so the descriptor
+	// is read through the raw connection instead.
+	raw, err := timerfd.file.SyscallConn()
+	if err != nil {
+		return -1, err
+	}
+
+	fd := -1
+	if err := raw.Control(func(fdInControl uintptr) { fd = int(fdInControl) }); err != nil {
+		return -1, err
+	}
+
+	return fd, nil
+}
+
+// ErrTickerStopped is returned by SelectReady() when the ticker stops while
+// it waits.
+var ErrTickerStopped = errors.New("ticker stopped")
+
+// SelectReady waits, using epoll(7), until either the ticker ticks or one of
+// fds is ready to read, and returns which: FD() for the ticker, or the ready
+// fd.  If several are ready, any one of them may be returned.  This lets a
+// readiness-based event loop wait on the ticker and, for instance, a socket
+// at once.  It is Linux-only.
+//
+// The ticker's read goroutine still reads the timer and delivers ticks as
+// usual; SelectReady is woken when it does (through an eventfd that the
+// read goroutine signals while a SelectReady is waiting), and FD() is only
+// needed to recognize the result.  Only the ticks read after SelectReady is
+// called wake it.  SelectReady returns an error for a ticker that does not
+// use the timerfd backend, as FD() does.  Only one goroutine at a time
+// should call SelectReady on a ticker, because a tick wakes only one of
+// them.
+func (ticker *Ticker[T]) SelectReady(fds ...int) (readyFd int, err error) {
+	ticker.mu.Lock()
+	handles, stopped := ticker.handles, ticker.inStoppedState
+	ticker.mu.Unlock()
+
+	if stopped {
+		return -1, ErrTickerStopped
+	}
+	if _, err := ticker.FD(); err != nil {
+		return -1, err
+	}
+
+	notifyFd, err := handles.acquireTickNotifier()
+	if err != nil {
+		return -1, err
+	}
+	defer handles.releaseTickNotifier()
+
+	epollFd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return -1, err
+	}
+	defer unix.Close(epollFd)
+
+	for _, fd := range append([]int{notifyFd}, fds...) {
+		event := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(fd)}
+		if err := unix.EpollCtl(epollFd, unix.EPOLL_CTL_ADD, fd, &event); err != nil {
+			return -1, fmt.Errorf("on epoll_ctl() of fd %d: %w", fd, err)
+		}
+	}
+
+	events := make([]unix.EpollEvent, len(fds)+1)
+	for {
+		n, err := unix.EpollWait(epollFd, events, -1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return -1, err
+		}
+
+		for _, event := range events[:n] {
+			if int(event.Fd) != notifyFd {
+				return int(event.Fd), nil
+			}
+		}
+
+		// Only the notifier is ready: consume its count.
+		var count [8]byte
+		unix.Read(notifyFd, count[:])
+		if handles.isClosed() {
+			return -1, ErrTickerStopped
+		}
+
+		return ticker.FD()
+	}
+}
+
+// acquireTickNotifier returns the run's tick notifier, an eventfd that the
+// read goroutine signals after each read of the timer while it has users (and
+// close() signals when the run stops), creating it if necessary, and drains
+// it, so that only the ticks from then on are signalled.  Each
+// acquireTickNotifier must be paired with a releaseTickNotifier.  The eventfd
+// is closed by the last of close() and the releases, so it is never closed
+// while SelectReady waits on it.
+func (c *tickerHandles[T]) acquireTickNotifier() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.areClosed {
+		return -1, ErrTickerStopped
+	}
+
+	if c.tickNotifier < 0 {
+		fd, err := unix.Eventfd(0, unix.EFD_NONBLOCK|unix.EFD_CLOEXEC)
+		if err != nil {
+			return -1, err
+		}
+		c.tickNotifier = fd
+	} else {
+		var count [8]byte
+		unix.Read(c.tickNotifier, count[:])
+	}
+	c.tickNotifierUsers++
+	c.tickNotifierWaiting.Store(true)
+
+	return c.tickNotifier, nil
+}
+
+func (c *tickerHandles[T]) releaseTickNotifier() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tickNotifierUsers--
+	c.tickNotifierWaiting.Store(c.tickNotifierUsers > 0)
+	if c.areClosed && c.tickNotifierUsers == 0 {
+		unix.Close(c.tickNotifier)
+		c.tickNotifier = -1
+	}
+}
+
+// notifyTick signals the tick notifier, if it has users.  It is called by the
+// read goroutine, which takes the lock only while there are users, and by
+// close() with the lock held.
+func (c *tickerHandles[T]) notifyTick(locked bool) {
+	if !locked {
+		if !c.tickNotifierWaiting.Load() {
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+
+	if c.tickNotifier >= 0 && c.tickNotifierUsers > 0 {
+		one := [8]byte{1}
+		unix.Write(c.tickNotifier, one[:])
+	}
+}
@@ -0,0 +1,98 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestSelectReady(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(10 * time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timerFd, err := ticker.FD()
+	if err != nil {
+		t.Fatalf("on FD(): %s", err.Error())
+	}
+
+	var pipe [2]int
+	if err := unix.Pipe2(pipe[:], unix.O_CLOEXEC); err != nil {
+		t.Fatalf("on pipe2(): %s", err.Error())
+	}
+	defer unix.Close(pipe[0])
+	defer unix.Close(pipe[1])
+
+	readyFd, err := ticker.SelectReady(pipe[0])
+	if err != nil {
+		t.Fatalf("on SelectReady(): %s", err.Error())
+	}
+	if readyFd != timerFd {
+		t.Errorf("expected SelectReady() to return the timer fd (%d), got %d", timerFd, readyFd)
+	}
+
+	unix.Write(pipe[1], []byte{0})
+	if readyFd, err = ticker.SelectReady(pipe[0]); err != nil {
+		t.Fatalf("on SelectReady(): %s", err.Error())
+	}
+	if readyFd != pipe[0] && readyFd != timerFd {
+		t.Errorf("expected SelectReady() to return the pipe fd (%d), got %d", pipe[0], readyFd)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		ticker.Stop()
+	}()
+
+	unix.Read(pipe[0], make([]byte, 1))
+	for err == nil {
+		_, err = ticker.SelectReady(pipe[0])
+	}
+	if !errors.Is(err, hrtime.ErrTickerStopped) {
+		t.Errorf("expected ErrTickerStopped once the ticker stops, got %v", err)
+	}
+
+	if _, err := ticker.FD(); err == nil {
+		t.Errorf("expected FD() of a stopped ticker to fail")
+	}
+}
+
+func TestSelectReadyIgnoresEarlierTicks(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(20 * time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if _, err := ticker.SelectReady(); err != nil {
+		t.Fatalf("on SelectReady(): %s", err.Error())
+	}
+
+	// The ticks while nobody waits are not reported to the next call.
+	time.Sleep(70 * time.Millisecond)
+	calledAt := hrtime.MonotonicNow()
+	if _, err := ticker.SelectReady(); err != nil {
+		t.Fatalf("on SelectReady(): %s", err.Error())
+	}
+	if lastTick := ticker.LastTickAt(); lastTick < calledAt {
+		t.Errorf("expected SelectReady() to wait for a tick after it was called, but it returned for one %s before", calledAt.Sub(lastTick))
+	}
+}
+
+func TestSelectReadyRequiresTimerfd(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(hrtimetest.NewBackend()))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if readyFd, err := ticker.SelectReady(); err == nil {
+		t.Errorf("expected SelectReady() of a ticker without a timerfd to fail, got fd %d", readyFd)
+	}
+}
@@ -0,0 +1,116 @@
+package hrtime
+
+// WithSeparateSender splits the work of the read goroutine between two
+// goroutines: a reader, which only drains the timer, and adds the
+// expirations that it reads to a pending count, and a sender, which sends
+// the pending count on the channel when a receiver takes it, and starts a
+// new count.  The count is locked only for as long as it takes to change
+// it, so a consumer that is slow to receive never holds up the reads of the
+// timer: lateness, Stats(), the detection options and the heartbeats are
+// measured as promptly as if the consumer kept up, and the timer's
+// expirations are never left to accumulate in the kernel.
+//
+// The sender waits for a receiver as BlockingDelivery does, so a value is
+// never dropped; the ticks that accumulate while it waits are sent together,
+// as the next value.  The Lateness and FiredAt of a RichTick are those of the
+// most recent read when the value is sent, and its Seq counts the values the
+// sender has sent.  Because nothing is dropped, SetDeliveryMode(),
+// WithSkipMissed, WithHandoff, WithOverrunPause, WithOverrunAlarm and
+// WithAdaptiveDelivery have no effect.  It does not apply to a ticker with a
+// handler (see WithHandler), a ring buffer (see WithRingBuffer) or a
+// countdown (see WithCountdown).
+//
+// The extra goroutine, and the wake-up of the sender by the reader, cost a
+// little on every read, so it is only worthwhile at high rates, or with a
+// consumer whose receives are irregular; BenchmarkSeparateSender compares
+// it with the single read goroutine.
+func WithSeparateSender() Option {
+	return func(config *tickerConfig) {
+		config.separateSender = true
+	}
+}
+
+// A tickSender is the state that the reader shares with the sender of a run
+// with WithSeparateSender.
+type tickSender struct {
+	// pending is the number of ticks read and not yet sent, and latest is
+	// the most recent tick read.  Both are protected by the handles lock, so
+	// that the two agree.
+	pending uint64
+	latest  tickInfo
+
+	// wake has room for one signal, which the reader leaves after adding to
+	// pending, so that the sender never misses an addition.  exited is
+	// closed when the sender returns.
+	wake   chan struct{}
+	exited chan struct{}
+}
+
+// newTickSender returns nil if the run does not use a separate sender.
+func (ticker *Ticker[T]) newTickSender() *tickSender {
+	config := &ticker.config
+	if !config.separateSender || config.handler != nil || ticker.ring != nil || config.countdown > 0 {
+		return nil
+	}
+
+	return &tickSender{
+		wake:   make(chan struct{}, 1),
+		exited: make(chan struct{}),
+	}
+}
+
+// handToSender is called by the reader in place of a send.  The ticks of
+// tick are left to the sender, so they count as delivered.
+func (ticker *Ticker[T]) handToSender(handles *tickerHandles[T], tick *tickInfo) {
+	sender := handles.sender
+
+	handles.mu.Lock()
+	sender.latest = *tick
+	sender.pending += tick.delta
+	handles.mu.Unlock()
+
+	select {
+	case sender.wake <- struct{}{}:
+	default:
+	}
+
+	tick.delta = 0
+}
+
+// sendLoop is the sender goroutine of a run with a separate sender.  It
+// returns once the handles are closed.
+func (ticker *Ticker[T]) sendLoop(handles *tickerHandles[T]) {
+	sender := handles.sender
+	defer close(sender.exited)
+
+	sequence := uint64(1)
+	for {
+		select {
+		case <-sender.wake:
+		case <-handles.closed:
+			return
+		}
+
+		handles.mu.Lock()
+		delta := sender.pending
+		sender.pending = 0
+		tick := sender.latest
+		handles.mu.Unlock()
+		if delta == 0 {
+			continue
+		}
+		tick.delta, tick.sequence = delta, sequence
+
+		if sent, _ := handles.sendBlocking(ticker.makeTick(tick), 0); !sent {
+			return
+		}
+
+		handles.deliveredCount.Add(1)
+		if !tick.warmingUp {
+			handles.stats.recordDelivery(true)
+		}
+		handles.lastSentAt.Store(int64(tick.firedAt))
+		handles.lastCoalesced.Store(delta > 1)
+		sequence++
+	}
+}
@@ -0,0 +1,102 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestSeparateSender(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewRichTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithSeparateSender())
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	// The reader keeps reading while nobody receives.
+	timer := backend.LastTimer()
+	for i := 0; i < 3; i++ {
+		timer.Advance(1)
+		timer.WaitForReader()
+	}
+	for deadline := time.Now().Add(5 * time.Second); ticker.Stats().Expirations != 3; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 expirations to be read, got %d", ticker.Stats().Expirations)
+		}
+	}
+
+	// The ticks the sender could not send meanwhile are sent together.
+	var received, values uint64
+	for received < 3 {
+		tick := <-ticker.C
+		values++
+		received += tick.Delta
+		if tick.Seq != values || tick.Cumulative != received {
+			t.Errorf("expected value %d to have seq %d and a cumulative count of %d, got %d and %d", values, values, received, tick.Seq, tick.Cumulative)
+		}
+	}
+	if received != 3 || values > 2 {
+		t.Errorf("expected 3 ticks in at most 2 values, got %d in %d", received, values)
+	}
+	if err := ticker.Stop(); err != nil {
+		t.Fatalf("on Stop(): %s", err.Error())
+	}
+	if _, open := <-ticker.C; open {
+		t.Errorf("expected the channel to be closed after Stop()")
+	}
+	if stats := ticker.Stats(); stats.Deliveries != values || stats.Drops != 0 {
+		t.Errorf("expected %d deliveries and no drops, got %d and %d", values, stats.Deliveries, stats.Drops)
+	}
+}
+
+// BenchmarkSeparateSender compares the single read goroutine with a separate
+// sender at a high rate, with a consumer whose work on every other tick is a
+// little longer than the interval.  It reports the mean lateness of the timer
+// reads, which the single goroutine inflates by the time it spends sending,
+// the latency with which the oldest tick of each value is received, and the
+// mean number of ticks per value.  The consumer spins while it works, so the
+// comparison is only meaningful with at least 2 CPUs.
+func BenchmarkSeparateSender(b *testing.B) {
+	const interval = 10 * time.Microsecond
+
+	for _, mode := range []struct {
+		name string
+		opts []hrtime.Option
+	}{
+		{"single", nil},
+		{"separate", []hrtime.Option{hrtime.WithSeparateSender()}},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			ticker := hrtime.NewRichTicker(interval, mode.opts...)
+			if err := ticker.Start(); err != nil {
+				b.Fatalf("on Start(): %s", err.Error())
+			}
+			defer ticker.Stop()
+
+			var total time.Duration
+			var ticks uint64
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tick := <-ticker.C
+				receivedAt := hrtime.MonotonicNow()
+				oldest := tick.FiredAt.Add(-tick.Lateness - time.Duration(tick.Delta-1)*interval)
+				total += receivedAt.Sub(oldest)
+				ticks += tick.Delta
+
+				work := interval / 2
+				if i%2 == 0 {
+					work = interval * 12 / 10
+				}
+				for deadline := receivedAt.Add(work); hrtime.MonotonicNow() < deadline; {
+				}
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(ticker.Stats().MeanLateness.Nanoseconds()), "ns-mean-lateness")
+			b.ReportMetric(float64(total.Nanoseconds())/float64(b.N), "ns-mean-latency")
+			b.ReportMetric(float64(ticks)/float64(b.N), "ticks/value")
+		})
+	}
+}
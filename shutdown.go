@@ -0,0 +1,99 @@
+package hrtime
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrShuttingDown is returned by Start() once Shutdown() has been called, and
+// is the reason given to OnStop() for the runs that Shutdown() stops.
+var ErrShuttingDown = errors.New("hrtime is shutting down")
+
+// The runs that are in progress, whether Shutdown() has been called, and
+// whether PauseAll() is in effect.  A run is registered by Start(), and
+// unregistered when its read goroutine exits.
+var activeRuns = struct {
+	mu           sync.Mutex
+	runs         map[any]*activeRun
+	shuttingDown bool
+	pausedAll    bool
+}{runs: make(map[any]*activeRun)}
+
+// An activeRun holds the functions that Shutdown(), PauseAll() and
+// ResumeAll() apply to a run, and whether PauseAll() paused it.
+type activeRun struct {
+	stop             func()
+	pause            func() bool
+	resume           func() bool
+	pausedByPauseAll bool
+}
+
+// Shutdown begins a process-wide teardown: every ticker that is running (or
+// paused) is stopped, as if Stop() had been called, with ErrShuttingDown as
+// the reason given to OnStop(), and every later Start() of any ticker, or
+// StartPaused(), returns ErrShuttingDown.  It does not wait for the read
+// goroutines to exit; see WaitStopped().  Calling it again stops nothing more.
+//
+// A Start() that is in progress when Shutdown() is called either completes
+// before Shutdown() takes its list of running tickers, in which case the ticker
+// is stopped with the others, or returns ErrShuttingDown, so no ticker is
+// left running.  Shutdown() applies to the tickers of this package (including
+// the members of a PhaseLockedGroup), but not to a ScheduleTicker.
+func Shutdown() {
+	activeRuns.mu.Lock()
+	activeRuns.shuttingDown = true
+	stops := make([]func(), 0, len(activeRuns.runs))
+	for _, run := range activeRuns.runs {
+		stops = append(stops, run.stop)
+	}
+	activeRuns.mu.Unlock()
+
+	for _, stop := range stops {
+		stop()
+	}
+}
+
+// registerRun records that the run with handles is in progress, unless
+// Shutdown() has been called, in which case it returns ErrShuttingDown.  It
+// is called by start() once the run can no longer fail to start.  If
+// PauseAll() is in effect and the run is not to start paused, registerRun
+// disarms its timer, and reports that it must start paused instead.
+func (ticker *Ticker[T]) registerRun(handles *tickerHandles[T], paused bool) (pausedAll bool, err error) {
+	activeRuns.mu.Lock()
+	defer activeRuns.mu.Unlock()
+
+	if activeRuns.shuttingDown {
+		return false, ErrShuttingDown
+	}
+
+	run := &activeRun{
+		stop: func() {
+			ticker.endRun(handles, ErrShuttingDown)
+		},
+		pause: func() bool {
+			return ticker.pauseRun(handles)
+		},
+		resume: func() bool {
+			return ticker.resumeRun(handles)
+		},
+	}
+	if activeRuns.pausedAll && !paused {
+		if err := handles.timer.Settime(0, &unix.ItimerSpec{}); err != nil {
+			return false, err
+		}
+		run.pausedByPauseAll = true
+	}
+	activeRuns.runs[handles] = run
+
+	return run.pausedByPauseAll, nil
+}
+
+// unregisterRun is called by the read goroutine of the run with handles as it
+// exits.
+func (c *tickerHandles[T]) unregisterRun() {
+	activeRuns.mu.Lock()
+	delete(activeRuns.runs, c)
+	activeRuns.mu.Unlock()
+}
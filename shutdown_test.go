@@ -0,0 +1,83 @@
+package hrtime_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestShutdown(t *testing.T) {
+	defer hrtime.ResetShutdown()
+
+	running := hrtime.NewMonotonicTicker(time.Millisecond)
+	paused := hrtime.NewRichTicker(time.Millisecond)
+	reasons := make(chan error, 2)
+	running.OnStop(func(reason error) { reasons <- reason })
+	paused.OnStop(func(reason error) { reasons <- reason })
+
+	if err := running.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	if err := paused.StartPaused(); err != nil {
+		t.Fatalf("on StartPaused(): %s", err.Error())
+	}
+
+	hrtime.Shutdown()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case reason := <-reasons:
+			if !errors.Is(reason, hrtime.ErrShuttingDown) {
+				t.Errorf("expected tickers to stop with ErrShuttingDown, got %v", reason)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected Shutdown() to stop every running ticker")
+		}
+	}
+
+	if err := running.Start(); !errors.Is(err, hrtime.ErrShuttingDown) {
+		t.Errorf("expected Start() after Shutdown() to return ErrShuttingDown, got %v", err)
+	}
+	if err := hrtime.NewMonotonicTicker(time.Millisecond).StartPaused(); !errors.Is(err, hrtime.ErrShuttingDown) {
+		t.Errorf("expected StartPaused() after Shutdown() to return ErrShuttingDown, got %v", err)
+	}
+}
+
+func TestShutdownDuringStarts(t *testing.T) {
+	defer hrtime.ResetShutdown()
+
+	tickers := make([]*hrtime.MonotonicTicker, 20)
+	errs := make([]error, len(tickers))
+	var wg sync.WaitGroup
+	for i := range tickers {
+		tickers[i] = hrtime.NewMonotonicTicker(time.Millisecond)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = tickers[i].Start()
+		}(i)
+	}
+	hrtime.Shutdown()
+	wg.Wait()
+
+	// Every ticker either refused to start or was stopped by Shutdown().
+	for i, ticker := range tickers {
+		if errs[i] != nil && !errors.Is(errs[i], hrtime.ErrShuttingDown) {
+			t.Errorf("on Start() of ticker %d: %s", i, errs[i].Error())
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			ticker.WaitStopped()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected ticker %d to be left stopped", i)
+		}
+	}
+}
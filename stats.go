@@ -0,0 +1,322 @@
+package hrtime
+
+import (
+	"math/bits"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stats describes a ticker's ticks since it was last started, or since the
+// last call to SnapshotAndReset().
+type Stats struct {
+	// Expirations is the number of timer expirations read.
+	Expirations uint64
+
+	// Deliveries is the number of values sent on the channel or, for a
+	// ticker with a handler (see WithHandler), the number of handler calls.
+	Deliveries uint64
+
+	// Drops is the number of times a value could not be sent on the channel
+	// because no receiver was ready.  By default, the ticks are not lost:
+	// they are carried over to the next value that is sent.  With
+	// WithSkipMissed, they are discarded.
+	Drops uint64
+
+	// ReArms is the number of times the read goroutine re-armed the timer
+	// of a rate-scheduled ticker (see WithRate) for its next deadline.  It
+	// re-arms the one-shot timer once after each read, and each read
+	// reports the one expiration of the previous arming, so ReArms equals
+	// Expirations, except that deadlines that passed before the timer could
+	// be re-armed for them are counted in Expirations without a read of
+	// their own.  Any other difference means that the timer was armed more
+	// or less often than once per expiration.  The arming by Start() or
+	// Resume() is not counted.  ReArms is 0 for a periodic ticker.
+	ReArms uint64
+
+	// MeanLateness and MaxLateness are the mean and the greatest lateness
+	// of the timer reads, each relative to the nominal time of the most
+	// recent expiration that it read.
+	MeanLateness time.Duration
+	MaxLateness  time.Duration
+
+	// P99Lateness is an estimate of the 99th percentile of the lateness of
+	// the timer reads.  Lateness is counted in buckets whose bounds are
+	// powers of two nanoseconds, so the estimate is the upper bound of the
+	// bucket that holds the percentile (or MaxLateness, if that is lower):
+	// it is never below the true value, and is less than twice it.
+	P99Lateness time.Duration
+
+	// WakeupLatency describes how long after the nominal time of the most
+	// recent expiration each timer read returned, as measured on the
+	// ticker's clock by the read goroutine as soon as it wakes.  It is only
+	// collected with WithWakeupLatency.
+	WakeupLatency LatencyStats
+}
+
+// LatencyStats summarizes a series of latencies.  P99 is estimated as
+// Stats.P99Lateness is.
+type LatencyStats struct {
+	Min  time.Duration
+	Mean time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// WithWarmupTicks excludes the first n ticks after each Start() from the
+// ticker's statistics, so that they describe its steady state: the read
+// goroutine starts just after Start() returns, so the first ticks can be
+// measured late.  The ticks are still delivered as usual.  A timer read that
+// returns any of the first n ticks is excluded in full (so, if the timer
+// reports several ticks at once, a few more than n can be excluded):
+//
+//   - Stats(): its expirations, MeanLateness and MaxLateness, and the
+//     Deliveries and Drops of any delivery attempted with its ticks, are
+//     not counted.
+//   - WithOverrunAlarm: deliveries attempted with its ticks are not
+//     counted in the overrun ratio.
+//
+// DeliveredCount(), HandlerStats(), WithSlowHandlerWarning and the
+// detection options are not affected.
+func WithWarmupTicks(n uint64) Option {
+	return func(config *tickerConfig) {
+		config.warmupTicks = n
+	}
+}
+
+// WithWakeupLatency makes the ticker measure the wakeup latency of each timer
+// read, and report it in the WakeupLatency of Stats(): the time from the
+// nominal time of the most recent expiration (on the arming schedule) to a
+// clock_gettime(2) reading of the ticker's clock taken as soon as the read
+// returns, before any of the read's processing.  The kernel wakes the read
+// goroutine's thread promptly on expiration, so this mostly measures how
+// long the Go runtime took to schedule the goroutine, separately from the
+// lateness, which also includes the time the timestamp source and the
+// processing before it take.  The extra clock reading costs tens of
+// nanoseconds on each read, which is why it is not taken by default.  Like
+// the lateness, it excludes the ticks of WithWarmupTicks.
+func WithWakeupLatency() Option {
+	return func(config *tickerConfig) {
+		config.wakeupLatency = true
+	}
+}
+
+// Stats returns the statistics collected since the ticker was last started,
+// or since the last call to SnapshotAndReset().  It returns a zero Stats if
+// the ticker has never been started.
+func (ticker *Ticker[T]) Stats() Stats {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return Stats{}
+	}
+
+	return handles.stats.snapshot(false)
+}
+
+// SnapshotAndReset returns the same statistics as Stats(), and zeroes them
+// in the same step, so that consecutive calls report disjoint windows.  Each
+// update made by the read goroutine is counted in exactly one window.
+func (ticker *Ticker[T]) SnapshotAndReset() Stats {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return Stats{}
+	}
+
+	return handles.stats.snapshot(true)
+}
+
+// DeliveredCount returns the number of values sent on the channel (or, for a
+// ticker with a handler, the number of handler calls) since the ticker was
+// last started.  Values that could not be sent are not counted.  Unlike the
+// Deliveries of Stats(), it is not zeroed by SnapshotAndReset(), and it is
+// cheap enough to poll: it is a single atomic load.  It returns 0 if the
+// ticker has never been started.
+func (ticker *Ticker[T]) DeliveredCount() uint64 {
+	ticker.mu.Lock()
+	handles := ticker.handles
+	ticker.mu.Unlock()
+
+	if handles == nil {
+		return 0
+	}
+
+	return handles.deliveredCount.Load()
+}
+
+// StatsString returns a one-line summary of the ticker for logs: its
+// interval, its Uptime(), and its Stats() -- the expirations, the deliveries,
+// the drops and, as jitter, the P99Lateness -- as in
+//
+//	interval=1ms uptime=2.5s expirations=2500 delivered=2498 dropped=2 p99=12.288µs
+//
+// The format is stable: the fields are always present, always in this
+// order, and always separated by single spaces, so that logs can be searched
+// for them.  Durations are formatted by time.Duration's String().  Like
+// Stats(), it can be called at any time, concurrently with the read
+// goroutine.
+func (ticker *Ticker[T]) StatsString() string {
+	ticker.mu.Lock()
+	interval := ticker.desiredInterval
+	ticker.mu.Unlock()
+
+	uptime := ticker.Uptime()
+	stats := ticker.Stats()
+
+	b := make([]byte, 0, 128)
+	b = append(b, "interval="...)
+	b = append(b, interval.String()...)
+	b = append(b, " uptime="...)
+	b = append(b, uptime.String()...)
+	b = append(b, " expirations="...)
+	b = strconv.AppendUint(b, stats.Expirations, 10)
+	b = append(b, " delivered="...)
+	b = strconv.AppendUint(b, stats.Deliveries, 10)
+	b = append(b, " dropped="...)
+	b = strconv.AppendUint(b, stats.Drops, 10)
+	b = append(b, " p99="...)
+	b = append(b, stats.P99Lateness.String()...)
+
+	return string(b)
+}
+
+// tickStats accumulates the values reported by Stats().
+type tickStats struct {
+	mu       sync.Mutex
+	counters tickCounters
+}
+
+type tickCounters struct {
+	expirations   uint64
+	deliveries    uint64
+	drops         uint64
+	rearms        uint64
+	lateness      durationSummary
+	wakeupLatency durationSummary
+}
+
+func (stats *tickStats) recordRead(expirations uint64, lateness time.Duration, rearmed bool) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.counters.expirations += expirations
+	stats.counters.lateness.record(lateness)
+	if rearmed {
+		stats.counters.rearms++
+	}
+}
+
+func (stats *tickStats) recordWakeupLatency(latency time.Duration) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.counters.wakeupLatency.record(latency)
+}
+
+func (stats *tickStats) recordDelivery(delivered bool) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if delivered {
+		stats.counters.deliveries++
+	} else {
+		stats.counters.drops++
+	}
+}
+
+func (stats *tickStats) snapshot(reset bool) Stats {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	counters := &stats.counters
+	snapshot := Stats{
+		Expirations:   counters.expirations,
+		Deliveries:    counters.deliveries,
+		Drops:         counters.drops,
+		ReArms:        counters.rearms,
+		MeanLateness:  counters.lateness.mean(),
+		MaxLateness:   counters.lateness.max,
+		P99Lateness:   counters.lateness.percentile(0.99),
+		WakeupLatency: counters.wakeupLatency.latencyStats(),
+	}
+
+	if reset {
+		*counters = tickCounters{}
+	}
+
+	return snapshot
+}
+
+// A durationSummary accumulates the count, the mean, the extremes and an
+// estimate of the percentiles of a series of durations.
+type durationSummary struct {
+	count   uint64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	buckets [64]uint64
+}
+
+// record adds d to the summary.  Bucket i counts the durations of at least
+// 2^(i-1)ns and less than 2^i ns, and bucket 0 those that are not positive.
+func (summary *durationSummary) record(d time.Duration) {
+	if summary.count == 0 || d < summary.min {
+		summary.min = d
+	}
+	if summary.count == 0 || d > summary.max {
+		summary.max = d
+	}
+	summary.count++
+	summary.total += d
+
+	bucket := 0
+	if d > 0 {
+		bucket = bits.Len64(uint64(d))
+	}
+	summary.buckets[bucket]++
+}
+
+func (summary *durationSummary) mean() time.Duration {
+	if summary.count == 0 {
+		return 0
+	}
+
+	return summary.total / time.Duration(summary.count)
+}
+
+// percentile returns the upper bound of the bucket that holds the percentile
+// p (in (0, 1]) of the recorded durations, capped at the greatest of them.
+func (summary *durationSummary) percentile(p float64) time.Duration {
+	if summary.count == 0 {
+		return 0
+	}
+
+	rank := uint64(p*float64(summary.count) + 0.5)
+	rank = min(max(rank, 1), summary.count)
+
+	var seen uint64
+	for i, count := range summary.buckets {
+		if seen += count; seen >= rank {
+			if i == 0 {
+				return min(0, summary.max)
+			}
+			return min(time.Duration(uint64(1)<<i-1), summary.max)
+		}
+	}
+
+	return summary.max
+}
+
+func (summary *durationSummary) latencyStats() LatencyStats {
+	return LatencyStats{
+		Min:  summary.min,
+		Mean: summary.mean(),
+		P99:  summary.percentile(0.99),
+		Max:  summary.max,
+	}
+}
@@ -0,0 +1,218 @@
+package hrtime_test
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickerStats(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithBackend(backend))
+
+	if stats := ticker.Stats(); stats != (hrtime.Stats{}) {
+		t.Errorf("expected zero Stats() before Start(), got %+v", stats)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// Nothing receives from the channel, so every send is dropped.
+	timer := backend.LastTimer()
+	timer.Advance(3)
+	timer.WaitForReader()
+	timer.Fire()
+	timer.WaitForReader()
+
+	stats := ticker.SnapshotAndReset()
+	if stats.Expirations != 4 || stats.Deliveries != 0 || stats.Drops != 2 {
+		t.Errorf("expected 4 expirations, 0 deliveries and 2 drops, got %+v", stats)
+	}
+	if stats.MaxLateness < stats.MeanLateness {
+		t.Errorf("expected MaxLateness >= MeanLateness, got %+v", stats)
+	}
+	if stats.P99Lateness > stats.MaxLateness {
+		t.Errorf("expected P99Lateness <= MaxLateness, got %+v", stats)
+	}
+
+	if stats := ticker.Stats(); stats != (hrtime.Stats{}) {
+		t.Errorf("expected zero Stats() after SnapshotAndReset(), got %+v", stats)
+	}
+}
+
+func TestTickerSnapshotAndResetIsConsistent(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	var windows hrtime.Stats
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				window := ticker.SnapshotAndReset()
+				windows.Expirations += window.Expirations
+				windows.Deliveries += window.Deliveries
+			}
+		}
+	}()
+
+	timer := backend.LastTimer()
+	for i := 0; i < 1000; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	close(stop)
+	wg.Wait()
+
+	final := ticker.Stats()
+	if total := windows.Expirations + final.Expirations; total != 1000 {
+		t.Errorf("expected 1000 expirations across windows, got %d", total)
+	}
+	if total := windows.Deliveries + final.Deliveries; total != 1000 {
+		t.Errorf("expected 1000 deliveries across windows, got %d", total)
+	}
+}
+
+func TestTickerDeliveredCount(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {}))
+
+	if count := ticker.DeliveredCount(); count != 0 {
+		t.Errorf("expected DeliveredCount() of 0 before Start(), got %d", count)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	timer := backend.LastTimer()
+	for i := 0; i < 5; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+	ticker.SnapshotAndReset()
+
+	if count := ticker.DeliveredCount(); count != 5 {
+		t.Errorf("expected DeliveredCount() of 5, unaffected by SnapshotAndReset(), got %d", count)
+	}
+
+	ticker.Stop()
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on second Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if count := ticker.DeliveredCount(); count != 0 {
+		t.Errorf("expected DeliveredCount() to start again at 0, got %d", count)
+	}
+}
+
+func TestTickerWarmupTicks(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithBackend(backend), hrtime.WithHandler(func(uint64) {}), hrtime.WithWarmupTicks(2))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	// The first tick is read late; the next is on time but still warm-up.
+	timer := backend.LastTimer()
+	timer.WaitForReader()
+	timer.Fire()
+	timer.AdvanceTime(5 * time.Millisecond)
+	timer.WaitForReader()
+	for i := 0; i < 4; i++ {
+		timer.Fire()
+		timer.WaitForReader()
+	}
+
+	if stats := ticker.Stats(); stats.Expirations != 3 || stats.Deliveries != 3 || stats.MaxLateness != 0 {
+		t.Errorf("expected 3 steady-state expirations and deliveries, all on time, got %+v", stats)
+	}
+	if count := ticker.DeliveredCount(); count != 5 {
+		t.Errorf("expected all 5 ticks to be delivered, got %d", count)
+	}
+}
+
+func TestTickerStatsString(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(10*time.Millisecond, hrtime.WithBackend(backend))
+
+	if summary := ticker.StatsString(); summary != "interval=10ms uptime=0s expirations=0 delivered=0 dropped=0 p99=0s" {
+		t.Errorf("unexpected StatsString() before Start(): %q", summary)
+	}
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.Advance(3)
+	timer.WaitForReader()
+	timer.Fire()
+	timer.WaitForReader()
+
+	format := regexp.MustCompile(`^interval=10ms uptime=\S+ expirations=4 delivered=0 dropped=2 p99=\S+$`)
+	if summary := ticker.StatsString(); !format.MatchString(summary) {
+		t.Errorf("unexpected StatsString(): %q", summary)
+	}
+}
+
+func TestTickerP99Lateness(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithHandler(func(uint64) {}))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	time.Sleep(50 * time.Millisecond)
+	ticker.Stop()
+
+	stats := ticker.Stats()
+	if stats.P99Lateness <= 0 || stats.P99Lateness > stats.MaxLateness {
+		t.Errorf("expected 0 < P99Lateness <= MaxLateness, got %+v", stats)
+	}
+}
+
+func TestTickerWakeupLatency(t *testing.T) {
+	for _, collect := range []bool{false, true} {
+		var opts []hrtime.Option
+		if collect {
+			opts = append(opts, hrtime.WithWakeupLatency())
+		}
+		ticker := hrtime.NewMonotonicTicker(time.Millisecond, append(opts, hrtime.WithHandler(func(uint64) {}))...)
+		if err := ticker.Start(); err != nil {
+			t.Fatalf("on Start(): %s", err.Error())
+		}
+		time.Sleep(50 * time.Millisecond)
+		ticker.Stop()
+
+		latency := ticker.Stats().WakeupLatency
+		if !collect {
+			if latency != (hrtime.LatencyStats{}) {
+				t.Errorf("expected no WakeupLatency without WithWakeupLatency(), got %+v", latency)
+			}
+			continue
+		}
+		if latency.Min <= 0 || latency.Min > latency.Mean || latency.Mean > latency.Max || latency.P99 > latency.Max || latency.P99 < latency.Min {
+			t.Errorf("expected 0 < Min <= Mean, P99 <= Max, got %+v", latency)
+		}
+	}
+}
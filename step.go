@@ -0,0 +1,154 @@
+package hrtime
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// A ClockStep describes a discontinuous change of a ticker's settable clock,
+// as detected by WithStepDetection.
+type ClockStep struct {
+	// Step is how far the clock jumped.  It is negative if the clock was
+	// stepped backward.
+	Step time.Duration
+
+	// DetectedAt is the monotonic time at which the step was detected.
+	DetectedAt MonotonicTime
+}
+
+// WithStepDetection makes the ticker watch for steps of its clock, such as
+// an operator or NTP setting the system time.  Each time the read goroutine
+// wakes, it compares how far the ticker's clock advanced since the previous
+// wake with how far CLOCK_MONOTONIC advanced.  If the two differ by more
+// than threshold, onStep is called (from the read goroutine, so it must not
+// block for long) with the difference.
+//
+// Most steps can only be detected when the ticker wakes, so they are
+// reported on the first tick after they occur.  The exception is a timer
+// armed for an absolute time on ClockRealtime (by ResetAligned(), WithRate
+// or a PhaseLockedGroup, for instance), which a backward step would leave
+// waiting for its old deadline until the clock caught up with it.  Such a
+// timer is armed with TFD_TIMER_CANCEL_ON_SET, so that the kernel wakes the
+// read goroutine as soon as the clock is set: onStep is then called at once
+// (whatever the size of the step), and the timer is re-armed from the new
+// reading of the clock, for the next multiple of the interval (or, with
+// WithRate, for one tick from then).  An error in re-arming it is reported
+// on Errors().
+//
+// Detection only applies to clocks that can be set (ClockRealtime and
+// ClockTAI); it is disabled for ClockMonotonic and ClockBoottime, which are
+// immune to steps.  The kernel only cancels timers on CLOCK_REALTIME, so a
+// ClockTAI ticker's steps are always reported on the next tick.
+func WithStepDetection(threshold time.Duration, onStep func(step ClockStep)) Option {
+	return func(config *tickerConfig) {
+		config.stepThreshold = threshold
+		config.onStep = onStep
+	}
+}
+
+func clockIsSettable(clock ClockID) bool {
+	return clock == ClockRealtime || clock == ClockTAI
+}
+
+// absoluteArmFlags returns the flags with which the ticker arms a timer on
+// clock for an absolute time: TFD_TIMER_ABSTIME and, with step detection on
+// a settable clock, TFD_TIMER_CANCEL_ON_SET (which the kernel ignores for
+// clocks other than CLOCK_REALTIME).
+func (config *tickerConfig) absoluteArmFlags(clock ClockID) int {
+	if config.onStep == nil || !clockIsSettable(clock) {
+		return unix.TFD_TIMER_ABSTIME
+	}
+
+	return unix.TFD_TIMER_ABSTIME | unix.TFD_TIMER_CANCEL_ON_SET
+}
+
+// clockWasSet is called by the read loop when a read of the timer failed with
+// ECANCELED, because its clock was set while it was armed with
+// TFD_TIMER_CANCEL_ON_SET.  It reports the step, and re-arms the timer from
+// the new reading of the clock.
+func (ticker *Ticker[T]) clockWasSet(handles *tickerHandles[T], steps *stepDetector) {
+	ticker.config.onStep(steps.measure())
+
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.inStoppedState || ticker.inPausedState || ticker.handles != handles {
+		return
+	}
+
+	handles.mu.Lock()
+	interval := handles.schedule.interval
+	handles.mu.Unlock()
+
+	var err error
+	if ticker.rate != 0 || interval <= 0 {
+		err = ticker.arm(handles, false, 0)
+	} else if now, nowErr := handles.timer.Now(); nowErr != nil {
+		err = nowErr
+	} else {
+		err = ticker.armAt(handles, (now/interval+1)*interval, interval)
+	}
+	if err != nil {
+		handles.reportError(fmt.Errorf("on re-arming the timer after a clock step: %w", err))
+	}
+}
+
+// A stepDetector compares successive readings of a settable clock with
+// readings of CLOCK_MONOTONIC.
+type stepDetector struct {
+	clock     ClockID
+	threshold time.Duration
+	lastClock time.Duration
+	lastMono  time.Duration
+}
+
+// newStepDetector returns nil if step detection is not configured, or not
+// applicable to clock.
+func newStepDetector(config *tickerConfig, clock ClockID) *stepDetector {
+	if config.onStep == nil || !clockIsSettable(clock) {
+		return nil
+	}
+
+	detector := &stepDetector{clock: clock, threshold: config.stepThreshold}
+	detector.lastClock, detector.lastMono, _ = detector.sample()
+
+	return detector
+}
+
+func (detector *stepDetector) sample() (clock time.Duration, mono time.Duration, err error) {
+	if clock, err = clockNow(int32(detector.clock)); err != nil {
+		return 0, 0, err
+	}
+	if mono, err = clockNow(unix.CLOCK_MONOTONIC); err != nil {
+		return 0, 0, err
+	}
+
+	return clock, mono, nil
+}
+
+// check takes a new sample, and returns the step since the previous sample
+// and true if that step exceeds the threshold.
+func (detector *stepDetector) check() (ClockStep, bool) {
+	step := detector.measure()
+	if step.Step > detector.threshold || step.Step < -detector.threshold {
+		return step, true
+	}
+
+	return ClockStep{}, false
+}
+
+// measure takes a new sample, and returns the step since the previous
+// sample, whatever its size.  If the clocks cannot be read, the step is 0.
+func (detector *stepDetector) measure() ClockStep {
+	clock, mono, err := detector.sample()
+	if err != nil {
+		return ClockStep{}
+	}
+
+	step := (clock - detector.lastClock) - (mono - detector.lastMono)
+	detector.lastClock, detector.lastMono = clock, mono
+
+	return ClockStep{Step: step, DetectedAt: MonotonicTime(mono)}
+}
@@ -0,0 +1,73 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+	"golang.org/x/sys/unix"
+)
+
+func TestStepDetectionHasNoFalsePositives(t *testing.T) {
+	steps := make(chan hrtime.ClockStep, 100)
+	ticker := hrtime.NewMonotonicTicker(5*time.Millisecond, hrtime.WithClock(hrtime.ClockRealtime), hrtime.WithStepDetection(time.Millisecond, func(step hrtime.ClockStep) {
+		steps <- step
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	for i := 0; i < 10; i++ {
+		<-ticker.C
+	}
+	ticker.Stop()
+
+	select {
+	case step := <-steps:
+		t.Errorf("expected no clock step while the clock was not set, got step of %s", step.Step)
+	default:
+	}
+}
+
+func TestStepDetectionCancelsAbsoluteTimer(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	steps := make(chan hrtime.ClockStep, 1)
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend), hrtime.WithClock(hrtime.ClockRealtime), hrtime.WithStepDetection(time.Hour, func(step hrtime.ClockStep) {
+		steps <- step
+	}))
+	ticker.SetDeliveryMode(hrtime.BlockingDelivery)
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.SetClock(100 * time.Second)
+	if err := ticker.ResetAligned(time.Second); err != nil {
+		t.Fatalf("on ResetAligned(): %s", err.Error())
+	}
+	if spec, flags := timer.LastSettime(); flags != unix.TFD_TIMER_ABSTIME|unix.TFD_TIMER_CANCEL_ON_SET || spec.Value.Nano() != int64(101*time.Second) {
+		t.Fatalf("expected an absolute arming for 101s that is cancelled on set, got %d (flags %#x)", spec.Value.Nano(), flags)
+	}
+
+	// A backward step would otherwise leave the timer waiting 91s for its
+	// deadline.
+	timer.SetClock(10 * time.Second)
+	select {
+	case <-steps:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the step to be reported when the clock was set")
+	}
+	timer.WaitForReader()
+	if spec, flags := timer.LastSettime(); flags&unix.TFD_TIMER_CANCEL_ON_SET == 0 || spec.Value.Nano() != int64(11*time.Second) {
+		t.Errorf("expected the timer to be re-armed for 11s on the new clock, got %d (flags %#x)", spec.Value.Nano(), flags)
+	}
+
+	timer.Fire()
+	if value := <-ticker.C; value != 1 {
+		t.Errorf("expected a tick at the re-armed deadline, got %d", value)
+	}
+}
@@ -0,0 +1,97 @@
+package hrtime
+
+import "fmt"
+
+// An OverrunError reports that a ticker in strict mode (see WithStrictMode)
+// missed ticks: a single read of its timer returned more than one
+// expiration, because the read goroutine woke more than an interval late.
+type OverrunError struct {
+	// Expirations is the number of expirations returned by the read; all
+	// but one of them were missed.
+	Expirations uint64
+
+	// At is the time, according to the ticker's TimestampSource, of the
+	// read.
+	At MonotonicTime
+}
+
+func (err *OverrunError) Error() string {
+	return fmt.Sprintf("timer overrun: %d expirations in one read (%d missed)", err.Expirations, err.Expirations-1)
+}
+
+// WithStrictMode makes the ticker report missed ticks rather than silently
+// coalescing them.  Whenever a read of the timer returns more than one
+// expiration, an *OverrunError is sent on the channel returned by Errors()
+// and, if stopOnOverrun is true, the ticker stops without delivering the
+// ticks (OnStop() is then given the *OverrunError as the reason).  Otherwise,
+// the ticks are delivered as usual, coalesced.  This only concerns ticks that
+// the read goroutine itself missed; ticks held back because the consumer was
+// not ready are coalesced (or, with WithSkipMissed, dropped) as usual.
+//
+// Strict mode costs nothing while the ticker keeps up, but it demands a lot
+// of the system: the read goroutine must wake within one interval of every
+// expiration, so intervals close to the timer latency of the machine (see
+// MinLatency) will overrun now and then even when idle, and garbage
+// collection pauses or CPU contention will cause overruns at any interval
+// shorter than their length.
+func WithStrictMode(stopOnOverrun bool) Option {
+	return func(config *tickerConfig) {
+		config.strict = true
+		config.stopOnOverrun = stopOnOverrun
+	}
+}
+
+// errorsBufferLength is the number of errors that the channel returned by
+// Errors() holds before further errors are discarded.
+const errorsBufferLength = 16
+
+// Errors returns the channel on which the ticker's current run reports
+// errors, such as the *OverrunError of WithStrictMode.  The channel is
+// buffered, and a send never blocks the read goroutine: errors that arrive
+// while it is full are discarded.  It is created by Start() and closed when
+// the run stops, like the ticker's channel, so it should be fetched again
+// after a restart.  It returns nil if the ticker has never been started.
+func (ticker *Ticker[T]) Errors() <-chan error {
+	ticker.mu.Lock()
+	defer ticker.mu.Unlock()
+
+	if ticker.handles == nil {
+		return nil
+	}
+
+	return ticker.handles.errors
+}
+
+// reportError sends err on the run's error channel, unless it is full or the
+// run has stopped.
+func (c *tickerHandles[T]) reportError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.areClosed {
+		return
+	}
+
+	select {
+	case c.errors <- err:
+	default:
+	}
+}
+
+// checkOverrun is called by the read loop after each read, in strict mode.
+// It reports an overrun if the read returned more than one expiration, and
+// returns true if the ticker was stopped because of it.
+func (ticker *Ticker[T]) checkOverrun(handles *tickerHandles[T], expirations uint64, at MonotonicTime) bool {
+	if !ticker.config.strict || expirations <= 1 {
+		return false
+	}
+
+	err := &OverrunError{Expirations: expirations, At: at}
+	handles.reportError(err)
+	if ticker.config.stopOnOverrun {
+		ticker.endRun(handles, err)
+		return true
+	}
+
+	return false
+}
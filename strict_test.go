@@ -0,0 +1,79 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestStrictModeReportsOverruns(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithStrictMode(false), hrtime.WithHandler(func(uint64) {}))
+
+	if errs := ticker.Errors(); errs != nil {
+		t.Errorf("expected no error channel before Start()")
+	}
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	timer := backend.LastTimer()
+	timer.Fire()
+	timer.WaitForReader()
+	timer.Advance(3)
+	timer.WaitForReader()
+
+	select {
+	case err := <-ticker.Errors():
+		var overrun *hrtime.OverrunError
+		if !errors.As(err, &overrun) || overrun.Expirations != 3 {
+			t.Errorf("expected an OverrunError for 3 expirations, got %v", err)
+		}
+	default:
+		t.Fatalf("expected an overrun to be reported")
+	}
+
+	select {
+	case err := <-ticker.Errors():
+		t.Errorf("expected a single overrun to be reported, got another: %v", err)
+	default:
+	}
+
+	if stats := ticker.Stats(); stats.Expirations != 4 || stats.Deliveries != 2 {
+		t.Errorf("expected the overrun ticks to be delivered, coalesced, got %+v", stats)
+	}
+}
+
+func TestStrictModeStopsOnOverrun(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Millisecond, hrtime.WithBackend(backend), hrtime.WithStrictMode(true), hrtime.WithHandler(func(uint64) {}))
+
+	reasons := make(chan error, 1)
+	ticker.OnStop(func(reason error) { reasons <- reason })
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	errs := ticker.Errors()
+
+	timer := backend.LastTimer()
+	timer.WaitForReader()
+	timer.Advance(2)
+
+	var overrun *hrtime.OverrunError
+	if reason := <-reasons; !errors.As(reason, &overrun) {
+		t.Errorf("expected the ticker to stop with an OverrunError, got %v", reason)
+	}
+	if err := <-errs; !errors.As(err, &overrun) {
+		t.Errorf("expected the OverrunError on the error channel, got %v", err)
+	}
+	if _, isOpen := <-errs; isOpen {
+		t.Errorf("expected the error channel to be closed once the ticker stops")
+	}
+	if stats := ticker.Stats(); stats.Deliveries != 0 {
+		t.Errorf("expected the overrun ticks not to be delivered, got %+v", stats)
+	}
+}
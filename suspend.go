@@ -0,0 +1,66 @@
+package hrtime
+
+import "time"
+
+// A Suspend describes a suspension of the system, as inferred by
+// WithSuspendDetection.
+type Suspend struct {
+	// Duration is how long the system was suspended, to within the
+	// precision of the two clock readings that revealed it.
+	Duration time.Duration
+
+	// DetectedAt is the monotonic time at which the suspension was
+	// detected, shortly after the system resumed.
+	DetectedAt MonotonicTime
+}
+
+// WithSuspendDetection makes the ticker infer suspensions of the system.
+// Each time the read goroutine wakes, it compares how far CLOCK_BOOTTIME
+// advanced since the previous wake with how far CLOCK_MONOTONIC advanced:
+// the two clocks advance together except while the system is suspended,
+// when only CLOCK_BOOTTIME does.  If CLOCK_BOOTTIME got ahead by more than
+// threshold, onResume is called (from the read goroutine, so it must not
+// block for long) with the difference.  This works with any clock; for a
+// ClockBoottime ticker, it tells the ticks caught up after a resume apart
+// from ordinary lateness.
+//
+// The heuristic has limits.  A suspension is only detected when the ticker
+// next wakes: a ClockBoottime ticker wakes as soon as the system resumes
+// (its timer expired during the suspension), but a ClockMonotonic ticker
+// only wakes once its next tick falls due in monotonic time, and a paused
+// ticker not until it is resumed.  Suspensions shorter than threshold are not
+// reported, and a threshold below a millisecond or so risks reporting the
+// noise between the two clock readings.  Several suspensions between two
+// wakes are reported as one.
+func WithSuspendDetection(threshold time.Duration, onResume func(suspend Suspend)) Option {
+	return func(config *tickerConfig) {
+		config.suspendThreshold = threshold
+		config.onResume = onResume
+	}
+}
+
+// newSuspendDetector returns nil if suspend detection is not configured.  A
+// suspension is detected as a forward step of CLOCK_BOOTTIME relative to
+// CLOCK_MONOTONIC.
+func newSuspendDetector(config *tickerConfig) *stepDetector {
+	if config.onResume == nil {
+		return nil
+	}
+
+	detector := &stepDetector{clock: ClockBoottime, threshold: config.suspendThreshold}
+	detector.lastClock, detector.lastMono, _ = detector.sample()
+
+	return detector
+}
+
+// checkSuspend checks detector, if it is not nil, for a suspension, and
+// reports any to onResume.
+func (config *tickerConfig) checkSuspend(detector *stepDetector) {
+	if detector == nil {
+		return
+	}
+
+	if step, stepped := detector.check(); stepped && step.Step > 0 {
+		config.onResume(Suspend{Duration: step.Step, DetectedAt: step.DetectedAt})
+	}
+}
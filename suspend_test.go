@@ -0,0 +1,30 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestSuspendDetectionHasNoFalsePositives(t *testing.T) {
+	suspends := make(chan hrtime.Suspend, 100)
+	ticker := hrtime.NewMonotonicTicker(5*time.Millisecond, hrtime.WithClock(hrtime.ClockBoottime), hrtime.WithSuspendDetection(time.Millisecond, func(suspend hrtime.Suspend) {
+		suspends <- suspend
+	}))
+
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	for i := 0; i < 10; i++ {
+		<-ticker.C
+	}
+	ticker.Stop()
+
+	select {
+	case suspend := <-suspends:
+		t.Errorf("expected no suspension while the system was running, got one of %s", suspend.Duration)
+	default:
+	}
+}
@@ -0,0 +1,62 @@
+package hrtime
+
+import "time"
+
+// The tolerances of CanSustain.  The warm-up ticks are excluded from the
+// trial's statistics, as with WithWarmupTicks.
+const (
+	sustainWarmupTicks        = 10
+	sustainMaxMissedFraction  = 0.01
+	sustainMaxLatenessPerTick = 0.1
+)
+
+// CanSustain runs a trial ticker at interval for duration, with a consumer
+// that receives every value as soon as it can, and reports whether this
+// system kept up with it, along with the statistics of the trial (from
+// which the first ten ticks are excluded, as by WithWarmupTicks).  The opts
+// are applied to the trial ticker, so that it can be run on the clock, or
+// backend, of the ticker that it is a trial for.  Ticks are only tracked
+// once the trial has started, so duration should be many intervals.
+//
+// The system keeps up if, over the trial, the read goroutine read (at least)
+// 99% of the expirations one at a time rather than coalesced, at most 1% of
+// the values had no receiver ready, and the mean lateness was at most a
+// tenth of interval.  It reports false, with a zero Stats, if the trial
+// ticker cannot be started.  The trial ticker is stopped, and its read
+// goroutine has exited, by the time CanSustain returns.  Its conclusion
+// only holds for the load at the time: a consumer that does more work per
+// tick, or a busier system, can sustain less.
+func CanSustain(interval time.Duration, duration time.Duration, opts ...Option) (bool, Stats) {
+	ticker := NewMonotonicTicker(interval, withOptions(opts, WithWarmupTicks(sustainWarmupTicks))...)
+	if err := ticker.Start(); err != nil {
+		return false, Stats{}
+	}
+
+	consumed := make(chan struct{})
+	go func() {
+		defer close(consumed)
+		for range ticker.C {
+		}
+	}()
+
+	time.Sleep(duration)
+	stats := ticker.Stats()
+	ticker.Stop()
+	ticker.WaitStopped()
+	<-consumed
+
+	return stats.sustained(interval), stats
+}
+
+// sustained reports whether stats, from a trial at interval, meet the
+// tolerances of CanSustain.
+func (stats Stats) sustained(interval time.Duration) bool {
+	if stats.Expirations == 0 {
+		return false
+	}
+
+	attempts := stats.Deliveries + stats.Drops
+	return float64(attempts) >= (1-sustainMaxMissedFraction)*float64(stats.Expirations) &&
+		float64(stats.Drops) <= sustainMaxMissedFraction*float64(attempts) &&
+		float64(stats.MeanLateness) <= sustainMaxLatenessPerTick*float64(interval)
+}
@@ -0,0 +1,36 @@
+package hrtime_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestCanSustain(t *testing.T) {
+	goroutinesBefore := runtime.NumGoroutine()
+
+	sustained, stats := hrtime.CanSustain(5*time.Millisecond, 200*time.Millisecond)
+	if !sustained {
+		t.Errorf("expected a 5ms interval to be sustainable, got %+v", stats)
+	}
+	if stats.Expirations < 20 || stats.Deliveries == 0 {
+		t.Errorf("expected the trial to tick, got %+v", stats)
+	}
+
+	if goroutines := runtime.NumGoroutine(); goroutines > goroutinesBefore {
+		t.Errorf("expected the trial to leave no goroutines behind, %d before and %d after", goroutinesBefore, goroutines)
+	}
+}
+
+func TestCannotSustain(t *testing.T) {
+	// A nanosecond interval overruns the read goroutine on every read.
+	if sustained, stats := hrtime.CanSustain(time.Nanosecond, 50*time.Millisecond); sustained {
+		t.Errorf("expected a 1ns interval not to be sustainable, got %+v", stats)
+	}
+
+	if sustained, _ := hrtime.CanSustain(time.Millisecond, 10*time.Millisecond, hrtime.WithClock(hrtime.ClockID(-1))); sustained {
+		t.Errorf("expected a trial ticker that cannot start not to be sustainable")
+	}
+}
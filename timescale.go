@@ -0,0 +1,82 @@
+package hrtime
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// timeScaleBits holds the math.Float64bits of the factor set by
+// SetTimeScale(), or 0 while it is 1.
+var timeScaleBits atomic.Uint64
+
+// SetTimeScale multiplies the timing of every ticker in the process by
+// factor, so that a simulation or a test can run faster (with a factor below
+// 1) or slower than real time without changing the code that creates its
+// tickers.  A factor of 0.01, for instance, makes a ticker with an interval of
+// a second tick every 10ms.
+//
+// The factor is applied each time a timer is armed: by Start(), Reset(),
+// Resume() and BoostInterval(), by the re-arm of WithCompletionPacing after
+// each handler call (for the scaled interval, less the time that the call
+// took), and by the Start() of a PhaseLockedGroup, a ScheduleTicker or a
+// BurstTicker.  A ticker that is already running keeps its current timing
+// until it is next armed.  It scales intervals, rates (see WithRate), the
+// durations of WithDuration, WithStartupJitter, WithMinDeliveryInterval,
+// WithConsumerIdleTimeout and BoostInterval(), the window of
+// WithAdaptiveDelivery, the minInterval of WithCompletionPacing, the offsets
+// of a ScheduleTicker, the period and burst spacing of a BurstTicker, and the
+// delay given to the Reset() of a OneShotTimer.  Intervals and offsets that
+// it brings below a nanosecond are rounded up to one.  It does not change
+// the interval that the ticker reports (as in Config()), nor the timestamps
+// of ticks, which stay in real time, nor absolute times (as used by
+// WaitUntil() and SetItimerSpec()).  A factor of 0 or less restores real
+// time.
+//
+// SetTimeScale is meant for tests and simulations; it affects every ticker
+// in the process, so it should be set before tickers are started.
+func SetTimeScale(factor float64) {
+	if factor <= 0 || factor == 1 {
+		timeScaleBits.Store(0)
+		return
+	}
+
+	timeScaleBits.Store(math.Float64bits(factor))
+}
+
+// TimeScale returns the factor set by SetTimeScale(), which is 1 unless it
+// has been changed.
+func TimeScale() float64 {
+	bits := timeScaleBits.Load()
+	if bits == 0 {
+		return 1
+	}
+
+	return math.Float64frombits(bits)
+}
+
+// scaled returns d multiplied by the time scale.
+func scaled(d time.Duration) time.Duration {
+	factor := TimeScale()
+	if factor == 1 || d <= 0 {
+		return d
+	}
+
+	scaledD := float64(d) * factor
+	if scaledD >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return max(time.Duration(math.Round(scaledD)), 1)
+}
+
+// scaledRate returns the rate (in micro-ticks per second) at which a ticker
+// with the provided rate ticks at the time scale.
+func scaledRate(rate uint64) uint64 {
+	factor := TimeScale()
+	if factor == 1 || rate == 0 {
+		return rate
+	}
+
+	return max(uint64(math.Round(float64(rate)/factor)), 1)
+}
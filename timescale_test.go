@@ -0,0 +1,81 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestSetTimeScale(t *testing.T) {
+	defer hrtime.SetTimeScale(1)
+
+	if factor := hrtime.TimeScale(); factor != 1 {
+		t.Errorf("expected default time scale of 1, got %f", factor)
+	}
+
+	hrtime.SetTimeScale(0.01)
+	if factor := hrtime.TimeScale(); factor != 0.01 {
+		t.Errorf("expected time scale of 0.01, got %f", factor)
+	}
+
+	backend := hrtimetest.NewBackend()
+	ticker := hrtime.NewMonotonicTicker(time.Second, hrtime.WithBackend(backend))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	if spec, _ := backend.LastTimer().LastSettime(); time.Duration(spec.Interval.Nano()) != 10*time.Millisecond {
+		t.Errorf("expected timer to be armed with a 10ms interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+	if interval := ticker.Config().Interval; interval != time.Second {
+		t.Errorf("expected Config() to report the unscaled interval of 1s, got %s", interval)
+	}
+
+	hrtime.SetTimeScale(0)
+	if factor := hrtime.TimeScale(); factor != 1 {
+		t.Errorf("expected SetTimeScale(0) to restore a time scale of 1, got %f", factor)
+	}
+
+	if spec, _ := backend.LastTimer().LastSettime(); time.Duration(spec.Interval.Nano()) != 10*time.Millisecond {
+		t.Errorf("expected running ticker to keep its 10ms interval until re-armed, got %s", time.Duration(spec.Interval.Nano()))
+	}
+
+	if err := ticker.Reset(time.Second); err != nil {
+		t.Fatalf("on Reset(): %s", err.Error())
+	}
+	if spec, _ := backend.LastTimer().LastSettime(); time.Duration(spec.Interval.Nano()) != time.Second {
+		t.Errorf("expected Reset() to arm the timer with a 1s interval, got %s", time.Duration(spec.Interval.Nano()))
+	}
+}
+
+func TestSetTimeScaleTicks(t *testing.T) {
+	defer hrtime.SetTimeScale(1)
+	hrtime.SetTimeScale(0.001)
+
+	ticker := hrtime.NewMonotonicTicker(time.Minute, hrtime.WithDuration(10*time.Minute))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("no tick received from a scaled 1m ticker")
+	}
+
+	deadline := time.After(10 * time.Second)
+	for {
+		select {
+		case _, ok := <-ticker.C:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("scaled WithDuration() did not stop the ticker")
+		}
+	}
+}
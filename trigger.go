@@ -0,0 +1,102 @@
+package hrtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// An EventSource says which source an Event came from.
+type EventSource int
+
+const (
+	// TickSource means that the event is a tick of the ticker.
+	TickSource EventSource = iota
+
+	// TriggerSource means that the event is a value received from the
+	// trigger channel.
+	TriggerSource
+)
+
+// String returns the name of the source, such as "TickSource".
+func (source EventSource) String() string {
+	switch source {
+	case TickSource:
+		return "TickSource"
+	case TriggerSource:
+		return "TriggerSource"
+	default:
+		return fmt.Sprintf("EventSource(%d)", int(source))
+	}
+}
+
+// An Event is delivered by TickOrTrigger for each tick, and for each value
+// received from the trigger channel.
+type Event[E any] struct {
+	// Source is the source of the event.
+	Source EventSource
+
+	// Ticks is, for a tick, the number of ticks since the previous tick
+	// event, as a MonotonicTicker counts them.  It is 0 for a trigger.
+	Ticks uint64
+
+	// Trigger is, for a trigger, the value received.  It is the zero value
+	// for a tick.
+	Trigger E
+}
+
+// TickOrTrigger starts a MonotonicTicker created with opts, and returns a
+// channel that merges its ticks with the values received from trigger, so
+// that a control loop can react, from a single receive, to whichever comes
+// first: the next tick, or a change signalled on trigger.  Each event says
+// which source it came from.  Events are delivered in the order that they
+// were received, by a goroutine that owns the ticker; while the consumer is
+// busy, ticks are coalesced into the next tick event, as on the ticker's
+// channel, and values from trigger wait to be received from it, so that
+// none is lost.
+//
+// When ctx is done, the ticker is stopped, the goroutine returns, and the
+// channel is closed, so cancelling ctx is how a consumer that is done
+// releases the ticker.  The channel is also closed if the ticker stops by
+// itself (see WithCountdown, for instance).  A trigger that is closed, or
+// nil, is ignored from then on, and the ticks continue.  If ctx is already
+// done, or the ticker cannot be started, no channel is returned.
+func TickOrTrigger[E any](ctx context.Context, interval time.Duration, trigger <-chan E, opts ...Option) (<-chan Event[E], error) {
+	ticker, err := NewMonotonicTickerWithContext(ctx, interval, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event[E])
+	go func() {
+		defer close(events)
+		defer ticker.Stop()
+
+		for {
+			var event Event[E]
+			select {
+			case ticks, open := <-ticker.C:
+				if !open {
+					return
+				}
+				event = Event[E]{Source: TickSource, Ticks: ticks}
+			case value, open := <-trigger:
+				if !open {
+					trigger = nil
+					continue
+				}
+				event = Event[E]{Source: TriggerSource, Trigger: value}
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,65 @@
+package hrtime_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+	"github.com/blorticus-go/hrtime/hrtimetest"
+)
+
+func TestTickOrTrigger(t *testing.T) {
+	backend := hrtimetest.NewBackend()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	trigger := make(chan string)
+	events, err := hrtime.TickOrTrigger(ctx, time.Second, trigger, hrtime.WithBackend(backend))
+	if err != nil {
+		t.Fatalf("on TickOrTrigger(): %s", err.Error())
+	}
+	timer := backend.LastTimer()
+
+	trigger <- "changed"
+	if event := <-events; event.Source != hrtime.TriggerSource || event.Trigger != "changed" || event.Ticks != 0 {
+		t.Errorf("expected the trigger's value, got %+v", event)
+	}
+
+	// A tick that finds the goroutine busy is carried over, so keep ticking
+	// until one is delivered.
+	nextTick := func() hrtime.Event[string] {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			timer.Advance(1)
+			select {
+			case event := <-events:
+				return event
+			case <-time.After(time.Millisecond):
+			}
+		}
+		t.Fatalf("expected a tick event")
+		return hrtime.Event[string]{}
+	}
+	if event := nextTick(); event.Source != hrtime.TickSource || event.Ticks == 0 {
+		t.Errorf("expected a tick, got %+v", event)
+	}
+
+	// Ticks continue once the trigger is closed.
+	close(trigger)
+	if event := nextTick(); event.Source != hrtime.TickSource {
+		t.Errorf("expected a tick after the trigger closed, got %+v", event)
+	}
+
+	cancel()
+	for range events {
+	}
+	if _, err := timer.ReadPending(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected the ticker's timer to be closed once the events are, got %v", err)
+	}
+
+	if _, err := hrtime.TickOrTrigger(ctx, time.Second, trigger); err == nil {
+		t.Errorf("expected TickOrTrigger() to fail with a done context")
+	}
+}
@@ -0,0 +1,93 @@
+package hrtime
+
+import (
+	"errors"
+	"math/bits"
+	"time"
+)
+
+// A TimestampSource provides the MonotonicTime readings that a ticker uses to
+// timestamp ticks.  The readings must be in the CLOCK_MONOTONIC domain, so
+// that they can be compared with the timestamps of other tickers.
+type TimestampSource interface {
+	Now() MonotonicTime
+}
+
+// clockGettimeSource is the default TimestampSource.  It reads
+// CLOCK_MONOTONIC using clock_gettime (which is normally serviced by the vDSO
+// without entering the kernel).
+type clockGettimeSource struct{}
+
+func (clockGettimeSource) Now() MonotonicTime {
+	return MonotonicNow()
+}
+
+// ErrTSCUnsupported is returned by NewTSCSource when the processor does not
+// provide an invariant TSC, or the platform is not amd64.
+var ErrTSCUnsupported = errors.New("invariant TSC is not available on this platform")
+
+// A TSCSource is a TimestampSource that reads the x86 time-stamp counter with
+// RDTSC, which is cheaper than clock_gettime.  TSC readings are converted to
+// MonotonicTime using a scale factor measured against CLOCK_MONOTONIC when
+// the source is created.
+//
+// The TSC is only a usable clock if it is invariant (it ticks at a constant
+// rate regardless of frequency scaling and power states), which NewTSCSource
+// checks using CPUID.  Even then, there are caveats: some hypervisors emulate
+// or trap RDTSC (making it slower than clock_gettime), virtual machine live
+// migration can change the TSC rate, some multi-socket systems do not
+// synchronize the TSC across sockets, and the calibrated scale factor does not
+// follow NTP adjustments of CLOCK_MONOTONIC, so readings slowly drift from it.
+// A long-running process should periodically replace its TSCSource with a
+// freshly calibrated one.
+type TSCSource struct {
+	baseTSC  uint64
+	baseTime MonotonicTime
+	// nanoseconds per TSC tick, as a 32.32 fixed-point value
+	scale uint64
+}
+
+// NewTSCSource creates a TSCSource, calibrating it by comparing the TSC to
+// CLOCK_MONOTONIC over the provided period.  Longer calibration periods yield
+// a more accurate scale factor; 10 ms or more is recommended.  If the
+// processor does not provide an invariant TSC, ErrTSCUnsupported is returned,
+// and the caller should fall back to the default source (for example, by not
+// using WithTimestampSource).
+func NewTSCSource(calibration time.Duration) (*TSCSource, error) {
+	if !hasInvariantTSC() {
+		return nil, ErrTSCUnsupported
+	}
+
+	startTSC, startTime := sampleTSC()
+	time.Sleep(calibration)
+	endTSC, endTime := sampleTSC()
+
+	if endTSC <= startTSC || endTime <= startTime {
+		return nil, ErrTSCUnsupported
+	}
+
+	hi, lo := bits.Mul64(uint64(endTime-startTime), 1<<32)
+	scale, _ := bits.Div64(hi, lo, endTSC-startTSC)
+
+	return &TSCSource{
+		baseTSC:  endTSC,
+		baseTime: endTime,
+		scale:    scale,
+	}, nil
+}
+
+// Now returns the current TSC reading, converted to MonotonicTime.
+func (source *TSCSource) Now() MonotonicTime {
+	hi, lo := bits.Mul64(rdtsc()-source.baseTSC, source.scale)
+	return source.baseTime + MonotonicTime(hi<<32|lo>>32)
+}
+
+// sampleTSC reads the TSC and CLOCK_MONOTONIC as close together as possible,
+// by bracketing the clock read with two TSC reads.
+func sampleTSC() (uint64, MonotonicTime) {
+	before := rdtsc()
+	now := MonotonicNow()
+	after := rdtsc()
+
+	return before + (after-before)/2, now
+}
@@ -0,0 +1,26 @@
+//go:build amd64
+
+package hrtime
+
+// rdtsc returns the value of the time-stamp counter.  It is implemented in
+// tsc_amd64.s.
+//
+//go:noescape
+func rdtsc() uint64
+
+// cpuid executes the CPUID instruction for the provided leaf and subleaf.
+// It is implemented in tsc_amd64.s.
+//
+//go:noescape
+func cpuid(leaf, subleaf uint32) (eax, ebx, ecx, edx uint32)
+
+func hasInvariantTSC() bool {
+	maxExtendedLeaf, _, _, _ := cpuid(0x80000000, 0)
+	if maxExtendedLeaf < 0x80000007 {
+		return false
+	}
+
+	// CPUID.80000007H:EDX[8] is the invariant TSC flag
+	_, _, _, edx := cpuid(0x80000007, 0)
+	return edx&(1<<8) != 0
+}
@@ -0,0 +1,11 @@
+//go:build !amd64
+
+package hrtime
+
+func rdtsc() uint64 {
+	return 0
+}
+
+func hasInvariantTSC() bool {
+	return false
+}
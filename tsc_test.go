@@ -0,0 +1,55 @@
+package hrtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestTSCSource(t *testing.T) {
+	source, err := hrtime.NewTSCSource(20 * time.Millisecond)
+	if errors.Is(err, hrtime.ErrTSCUnsupported) {
+		t.Skip("invariant TSC is not available")
+	}
+	if err != nil {
+		t.Fatalf("on NewTSCSource(): %s", err.Error())
+	}
+
+	tscStart, clockStart := source.Now(), hrtime.MonotonicNow()
+	time.Sleep(50 * time.Millisecond)
+	tscElapsed, clockElapsed := source.Now().Sub(tscStart), hrtime.MonotonicNow().Sub(clockStart)
+
+	if diff := tscElapsed - clockElapsed; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("expected TSC elapsed time (%s) to be within 1ms of clock elapsed time (%s)", tscElapsed, clockElapsed)
+	}
+
+	ticker := hrtime.NewMonotonicTicker(5*time.Millisecond, hrtime.WithTimestampSource(source))
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	<-ticker.C
+	if at := ticker.LastTickAt(); at.Sub(hrtime.MonotonicNow()) > time.Millisecond || hrtime.MonotonicNow().Sub(at) > 50*time.Millisecond {
+		t.Errorf("expected LastTickAt() to be near now, got %s ago", hrtime.MonotonicNow().Sub(at))
+	}
+}
+
+func BenchmarkTSCSourceNow(b *testing.B) {
+	source, err := hrtime.NewTSCSource(10 * time.Millisecond)
+	if err != nil {
+		b.Skip(err.Error())
+	}
+
+	for i := 0; i < b.N; i++ {
+		source.Now()
+	}
+}
+
+func BenchmarkMonotonicNow(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		hrtime.MonotonicNow()
+	}
+}
@@ -0,0 +1,53 @@
+package hrtime
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// WaitUntil blocks until the wall-clock time t, or until ctx is done, in
+// which case it returns ctx.Err().  It arms a one-shot timerfd on
+// CLOCK_REALTIME for the absolute time t, so unlike time.Sleep(time.Until(t))
+// no relative delay is computed (which would be late by however long passes
+// between computing it and sleeping), and if the realtime clock is stepped
+// while it waits, it still returns when the clock reaches t.  If t is not in
+// the future, WaitUntil returns nil immediately.
+func WaitUntil(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !t.After(time.Now()) {
+		return nil
+	}
+
+	timerFile, err := timerfdCreate(ClockRealtime)
+	if err != nil {
+		return err
+	}
+	defer timerFile.Close()
+
+	spec := &unix.ItimerSpec{Value: unix.NsecToTimespec(t.UnixNano())}
+	if err := settimeUsingFile(timerFile, unix.TFD_TIMER_ABSTIME, spec); err != nil {
+		return err
+	}
+
+	// Cancelling ctx interrupts the read by making it time out.
+	stopCancel := context.AfterFunc(ctx, func() {
+		timerFile.SetReadDeadline(time.Unix(0, 1))
+	})
+	defer stopCancel()
+
+	b := make([]byte, 8)
+	if _, err := timerFile.Read(b); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package hrtime_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestWaitUntil(t *testing.T) {
+	deadline := time.Now().Add(20 * time.Millisecond)
+	if err := hrtime.WaitUntil(context.Background(), deadline); err != nil {
+		t.Fatalf("on WaitUntil(): %s", err.Error())
+	}
+	if now := time.Now(); now.Before(deadline) || now.Sub(deadline) > 50*time.Millisecond {
+		t.Errorf("expected WaitUntil() to return shortly after the deadline, returned %s after it", now.Sub(deadline))
+	}
+
+	startedAt := time.Now()
+	if err := hrtime.WaitUntil(context.Background(), startedAt.Add(-time.Second)); err != nil {
+		t.Errorf("on WaitUntil() of a past time: %s", err.Error())
+	}
+	if elapsed := time.Since(startedAt); elapsed > 10*time.Millisecond {
+		t.Errorf("expected WaitUntil() of a past time to return immediately, took %s", elapsed)
+	}
+}
+
+func TestWaitUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	startedAt := time.Now()
+	if err := hrtime.WaitUntil(ctx, startedAt.Add(time.Hour)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(startedAt); elapsed > 500*time.Millisecond {
+		t.Errorf("expected WaitUntil() to return soon after cancellation, took %s", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := hrtime.WaitUntil(cancelled, time.Now().Add(-time.Second)); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled from an already-cancelled context, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package hrtime
+
+import "time"
+
+// A WallTick describes the ticks delivered by a WallTicker: how many there
+// were, and when the most recent was observed, both on CLOCK_MONOTONIC and on
+// the wall clock.
+type WallTick struct {
+	// Delta is the number of ticks that have occurred since the last
+	// channel read, as delivered by a MonotonicTicker.
+	Delta uint64
+
+	// FiredAtMono is the time, according to the ticker's TimestampSource,
+	// at which the read goroutine observed the most recent tick, as the
+	// FiredAt of a RichTick.
+	FiredAtMono MonotonicTime
+
+	// FiredAtWall is FiredAtMono converted to wall-clock (CLOCK_REALTIME)
+	// time.  It has no monotonic clock reading, so it should be used for
+	// correlation with external timestamps, not for measuring intervals.
+	FiredAtWall time.Time
+}
+
+// A WallTicker is a ticker that delivers a WallTick for each channel read.
+type WallTicker = Ticker[WallTick]
+
+// wallTickResampleInterval is how often a WallTicker re-samples the offset
+// between CLOCK_REALTIME and CLOCK_MONOTONIC.
+const wallTickResampleInterval = time.Second
+
+// NewWallTicker creates a ticker that is intended to fire a tick near every
+// interval and that delivers WallTick values, which carry the wall-clock
+// time of each tick as well as its monotonic time.  It accepts the same
+// options as NewMonotonicTicker.
+//
+// The wall-clock time is not read for each tick: FiredAtWall is FiredAtMono
+// plus the offset between the two clocks, which is sampled (as by a
+// WallClockCorrelator) when the ticker is created and again once it is a
+// second old, when a tick is delivered.  The estimate is therefore exact, to
+// within the tens of nanoseconds that a sample takes, just after a sample;
+// between samples, it drifts by as much as NTP slews the realtime clock in
+// a second (at most 500 microseconds, and normally well under a
+// microsecond).  If the realtime clock is stepped, FiredAtWall is off by the
+// step until the next sample.
+func NewWallTicker(interval time.Duration, opts ...Option) *WallTicker {
+	offset, sampledAt, _ := sampleWallClockOffset()
+
+	return newTicker(interval, opts, func(tick tickInfo) WallTick {
+		if tick.firedAt.Sub(sampledAt) >= wallTickResampleInterval {
+			if newOffset, newSampledAt, err := sampleWallClockOffset(); err == nil {
+				offset, sampledAt = newOffset, newSampledAt
+			}
+		}
+
+		return WallTick{
+			Delta:       tick.delta,
+			FiredAtMono: tick.firedAt,
+			FiredAtWall: time.Unix(0, int64(time.Duration(tick.firedAt)+offset)),
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package hrtime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestWallTicker(t *testing.T) {
+	ticker := hrtime.NewWallTicker(time.Millisecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+	defer ticker.Stop()
+
+	var previous hrtime.WallTick
+	for i := 0; i < 5; i++ {
+		tick := <-ticker.C
+		receivedAt := time.Now()
+
+		if tick.Delta == 0 {
+			t.Errorf("expected a positive Delta, got 0")
+		}
+		if lag := hrtime.MonotonicNow().Sub(tick.FiredAtMono); lag < 0 || lag > 100*time.Millisecond {
+			t.Errorf("expected FiredAtMono just before the tick was received, got %s before", lag)
+		}
+		if lag := receivedAt.Sub(tick.FiredAtWall); lag < -time.Millisecond || lag > 100*time.Millisecond {
+			t.Errorf("expected FiredAtWall just before the tick was received, got %s before", lag)
+		}
+		if i > 0 {
+			mono, wall := tick.FiredAtMono.Sub(previous.FiredAtMono), tick.FiredAtWall.Sub(previous.FiredAtWall)
+			if diff := wall - mono; diff < -10*time.Microsecond || diff > 10*time.Microsecond {
+				t.Errorf("expected wall-clock and monotonic intervals to agree, got %s and %s", wall, mono)
+			}
+		}
+		previous = tick
+	}
+}
@@ -0,0 +1,43 @@
+package hrtime
+
+import "sync"
+
+// WorkerPool starts n goroutines that receive from the ticker's channel and
+// call handle with each value, so that several goroutines share a single
+// ticker.  Each value is received by exactly one worker (any number of
+// goroutines may receive from the channel; this is the guarantee of a Go
+// channel, as the ticker sends each value once).  The workers exit once the
+// channel is closed, by Stop() or otherwise, and the returned channel is
+// closed when the last of them has returned from handle, for a clean
+// shutdown.
+//
+// Unlike WithHandlerPool, which dispatches from the read goroutine, this is a
+// consumer of the channel like any other: a tick that arrives while every
+// worker is busy is not dropped, but carried over to the next value that a
+// worker receives.  The workers serve the channel of the ticker's current run,
+// so WorkerPool must be called after Start(), and again after each restart
+// or SwapChannel().  An n of less than 1 starts one worker.
+func (ticker *Ticker[T]) WorkerPool(n int, handle func(T)) (done <-chan struct{}) {
+	ticker.mu.Lock()
+	c := ticker.C
+	ticker.mu.Unlock()
+
+	var workers sync.WaitGroup
+	for i := 0; i < max(n, 1); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for value := range c {
+				handle(value)
+			}
+		}()
+	}
+
+	allDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(allDone)
+	}()
+
+	return allDone
+}
@@ -0,0 +1,39 @@
+package hrtime_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/hrtime"
+)
+
+func TestWorkerPool(t *testing.T) {
+	ticker := hrtime.NewMonotonicTicker(200 * time.Microsecond)
+	if err := ticker.Start(); err != nil {
+		t.Fatalf("on Start(): %s", err.Error())
+	}
+
+	var mu sync.Mutex
+	calls := uint64(0)
+	done := ticker.WorkerPool(4, func(expirations uint64) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	ticker.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the workers to exit after Stop()")
+	}
+
+	// Each delivered value was handled by exactly one worker.
+	if delivered := ticker.DeliveredCount(); calls != delivered || calls == 0 {
+		t.Errorf("expected one handler call for each of the %d values delivered, got %d", delivered, calls)
+	}
+}